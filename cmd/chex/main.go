@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -14,6 +15,7 @@ import (
 var (
 	configFile   string
 	quiet        bool
+	verbose      bool
 	outputFormat string
 	rootDir      string
 	version      = "dev" // Will be set by build
@@ -27,7 +29,8 @@ var rootCmd = &cobra.Command{
 Examples:
   chex                    # Check all tools
   chex go docker          # Check only go and docker
-  chex --output=json      # Output in JSON format`,
+  chex --output=json      # Output in JSON format
+  chex --verbose          # Show the workdir and env of version commands`,
 	RunE:               runCheck,
 	DisableFlagParsing: false,
 	DisableAutoGenTag:  true,
@@ -36,6 +39,26 @@ Examples:
 	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: false},
 }
 
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration file for mistakes without running any tools",
+	Long: `validate reports unknown keys, invalid version constraints and regular expressions,
+missing CLIs, duplicate names and broken sources as file:line:col diagnostics.
+It exits with a non-zero status if any errors are found.`,
+	Args: cobra.NoArgs,
+	RunE: runValidate,
+}
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema for the configuration file",
+	Long: `schema prints a JSON Schema describing the configuration file (.chex.toml,
+.chex.yaml, .chex.yml or .chex.json), for editors such as Taplo (Even Better TOML)
+or the YAML language server to validate and autocomplete it.`,
+	Args: cobra.NoArgs,
+	RunE: runSchema,
+}
+
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Generate a sample .chex.toml configuration file",
@@ -43,17 +66,30 @@ var initCmd = &cobra.Command{
 }
 
 func init() {
-	rootCmd.Flags().StringVar(&configFile, "config", "", "config file (default: .chex.toml)")
+	rootCmd.PersistentFlags().StringVar(
+		&configFile,
+		"config",
+		"",
+		"config file (default: .chex.toml, .chex.yaml, .chex.yml or .chex.json)",
+	)
 	rootCmd.Flags().BoolVar(&quiet, "quiet", false, "only show failures")
+	rootCmd.Flags().BoolVar(
+		&verbose,
+		"verbose",
+		false,
+		"show the working directory and environment of version commands",
+	)
 	rootCmd.Flags().StringVar(
 		&outputFormat,
 		"output",
 		"pretty",
 		"output format (pretty|quiet|json)",
 	)
-	rootCmd.Flags().StringVar(&rootDir, "root", ".", "root directory to search for config")
+	rootCmd.PersistentFlags().StringVar(&rootDir, "root", ".", "root directory to search for config")
 
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(schemaCmd)
 	rootCmd.Version = version
 }
 
@@ -80,12 +116,24 @@ func runCheck(cmd *cobra.Command, args []string) error {
 		fmt.Fprintln(os.Stderr)
 	}
 
-	if len(loadResult.Tools) == 0 {
+	if len(loadResult.Tools) == 0 && len(loadResult.Env) == 0 &&
+		len(loadResult.Files) == 0 && len(loadResult.Services) == 0 {
 		return errors.New("no tools defined in configuration")
 	}
 
-	// Check tools (with optional filter)
-	results := checker.CheckAll(loadResult.Tools, args)
+	// Check tools (with optional filter) in the order they're declared
+	names := args
+	if len(names) == 0 {
+		names = loadResult.Order
+	}
+	results := checker.CheckAll(loadResult.Tools, names)
+
+	// Other requirements are only checked when no specific tools were requested
+	if len(args) == 0 {
+		results = append(results, checker.CheckEnvAll(loadResult.Env)...)
+		results = append(results, checker.CheckFileAll(loadResult.Files)...)
+		results = append(results, checker.CheckServiceAll(loadResult.Services)...)
+	}
 
 	// Check if any specified tool was not found
 	if len(args) > 0 {
@@ -102,7 +150,7 @@ func runCheck(cmd *cobra.Command, args []string) error {
 					result.Tool.Name,
 				)
 				fmt.Fprintln(os.Stderr, "Available tools:")
-				for name := range loadResult.Tools {
+				for _, name := range loadResult.Order {
 					fmt.Fprintf(os.Stderr, "  - %s\n", name)
 				}
 				return errors.New("invalid tool name")
@@ -124,6 +172,9 @@ func runCheck(cmd *cobra.Command, args []string) error {
 			outFormat = output.FormatPretty
 		}
 	}
+	if verbose && outFormat == output.FormatPretty {
+		outFormat = output.FormatVerbose
+	}
 
 	// Print results
 	output.Print(results, outFormat)
@@ -136,10 +187,40 @@ func runCheck(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runValidate(cmd *cobra.Command, args []string) error {
+	diagnostics, err := config.Validate(configFile, rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to validate configuration: %w", err)
+	}
+
+	for _, diagnostic := range diagnostics {
+		fmt.Println(diagnostic)
+	}
+
+	if config.HasErrors(diagnostics) {
+		os.Exit(1)
+	}
+	if len(diagnostics) == 0 {
+		fmt.Println("No problems found")
+	}
+
+	return nil
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(config.Schema()); err != nil {
+		return fmt.Errorf("failed to encode schema: %w", err)
+	}
+	return nil
+}
+
 func runInit(cmd *cobra.Command, args []string) error {
-	// Check if .chex.toml already exists
-	if _, err := os.Stat(".chex.toml"); err == nil {
-		return errors.New(".chex.toml already exists")
+	// Check if a config file already exists, in any format
+	if name := config.ExistingConfig("."); name != "" {
+		return fmt.Errorf("%s already exists", name)
 	}
 
 	// Generate sample configuration
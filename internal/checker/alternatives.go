@@ -0,0 +1,80 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// checkAlternatives checks each candidate CLI in order and passes with the first one
+// that satisfies the requirement.
+func checkAlternatives(tool *config.Tool, result *Result) *Result {
+	var failures []string
+
+	for _, candidate := range candidateTools(tool) {
+		candidateResult := checkCandidate(candidate, &Result{Tool: candidate})
+		if candidateResult.Status == StatusPass {
+			result.Status = StatusPass
+			result.Selected = candidate
+			result.InstalledVersion = candidateResult.InstalledVersion
+			result.Path = candidateResult.Path
+			result.Output = candidateResult.Output
+			return result
+		}
+		failures = append(failures, describeCandidateFailure(candidateResult))
+	}
+
+	result.Status = StatusFail
+	if tool.Optional {
+		result.Status = StatusOptionalMissing
+	}
+	result.Error = errors.New("no alternative satisfied the requirement: " + strings.Join(failures, "; "))
+	return result
+}
+
+// candidateTools expands a tool into one tool per candidate CLI.
+// The tool's own CLI, if set, is tried before its alternatives.
+func candidateTools(tool *config.Tool) []*config.Tool {
+	var candidates []*config.Tool
+
+	if tool.CLI != "" {
+		candidate := *tool
+		candidate.Alternatives = nil
+		candidates = append(candidates, &candidate)
+	}
+
+	for _, alt := range tool.Alternatives {
+		candidate := *tool
+		candidate.Alternatives = nil
+		candidate.CLI = alt.CLI
+		// Checksums pin the tool's own binary, which an alternative isn't
+		candidate.SHA256 = ""
+		candidate.SHA256File = ""
+		if alt.Version != "" {
+			candidate.Version = alt.Version
+		}
+		if alt.VersionArg != "" {
+			candidate.VersionArg = alt.VersionArg
+			candidate.VersionArgs = nil
+		}
+		if alt.VersionPattern != "" {
+			candidate.VersionPattern = alt.VersionPattern
+		}
+		candidates = append(candidates, &candidate)
+	}
+
+	return candidates
+}
+
+// describeCandidateFailure summarizes why a candidate didn't satisfy the requirement.
+func describeCandidateFailure(result *Result) string {
+	if result.Error != nil {
+		return result.Error.Error()
+	}
+	return fmt.Sprintf(
+		"%s: installed %s does not satisfy %s",
+		result.Tool.CLI, result.InstalledVersion, result.Tool.Version,
+	)
+}
@@ -0,0 +1,169 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+func TestCandidateTools(t *testing.T) {
+	tool := &config.Tool{
+		Name:       "container runtime",
+		CLI:        "docker",
+		Version:    ">=20.0.0",
+		VersionArg: "--version",
+		Alternatives: []config.Alternative{
+			{CLI: "podman"},
+			{CLI: "nerdctl", Version: ">=1.0.0", VersionArg: "version"},
+		},
+	}
+
+	candidates := candidateTools(tool)
+
+	if len(candidates) != 3 {
+		t.Fatalf("expected 3 candidates, got %d", len(candidates))
+	}
+
+	if candidates[0].CLI != "docker" {
+		t.Errorf("expected tool CLI to be tried first, got %q", candidates[0].CLI)
+	}
+
+	podman := candidates[1]
+	if podman.Version != ">=20.0.0" || podman.VersionArg != "--version" {
+		t.Errorf("expected podman to inherit version settings, got %+v", podman)
+	}
+
+	nerdctl := candidates[2]
+	if nerdctl.Version != ">=1.0.0" || nerdctl.VersionArg != "version" {
+		t.Errorf("expected nerdctl to use its own version settings, got %+v", nerdctl)
+	}
+
+	for _, candidate := range candidates {
+		if len(candidate.Alternatives) != 0 {
+			t.Errorf("expected candidate %q to have no alternatives", candidate.CLI)
+		}
+	}
+}
+
+func TestCandidateToolsChecksums(t *testing.T) {
+	tool := &config.Tool{
+		CLI:          "docker",
+		SHA256:       strings.Repeat("a", 64),
+		SHA256File:   "/etc/checksums.txt",
+		Alternatives: []config.Alternative{{CLI: "podman"}},
+	}
+
+	candidates := candidateTools(tool)
+
+	if candidates[0].SHA256 != tool.SHA256 || candidates[0].SHA256File != tool.SHA256File {
+		t.Errorf("expected docker to keep its checksums, got %+v", candidates[0])
+	}
+	if candidates[1].SHA256 != "" || candidates[1].SHA256File != "" {
+		t.Errorf("expected podman not to inherit docker's checksums, got %+v", candidates[1])
+	}
+}
+
+func TestCandidateToolsVersionArgs(t *testing.T) {
+	tool := &config.Tool{
+		CLI:         "helm",
+		VersionArgs: []string{"version", "--template", "{{.Version}}"},
+		Alternatives: []config.Alternative{
+			{CLI: "helm3"},
+			{CLI: "helm2", VersionArg: "version --client"},
+		},
+	}
+
+	candidates := candidateTools(tool)
+
+	if len(candidates[1].VersionArgs) != 3 {
+		t.Errorf("expected helm3 to inherit version_args, got %+v", candidates[1])
+	}
+	if candidates[2].VersionArgs != nil || candidates[2].VersionArg != "version --client" {
+		t.Errorf("expected helm2's version_arg to replace version_args, got %+v", candidates[2])
+	}
+}
+
+func TestCheckAlternatives(t *testing.T) {
+	t.Run("selects first satisfying alternative", func(t *testing.T) {
+		tool := &config.Tool{
+			Name:    "go toolchain",
+			CLI:     "nonexistent-tool-xyz",
+			Version: ">=1.0.0",
+			Alternatives: []config.Alternative{
+				{CLI: "nonexistent-tool-abc"},
+				{CLI: "go", VersionArg: "version"},
+			},
+		}
+
+		result := Check(tool)
+
+		if result.Status != StatusPass {
+			t.Fatalf("expected StatusPass, got %v (error: %v)", result.Status, result.Error)
+		}
+		if result.Selected == nil || result.Selected.CLI != "go" {
+			t.Errorf("expected go to be selected, got %+v", result.Selected)
+		}
+		if result.InstalledVersion == "" {
+			t.Error("expected installed version to be set")
+		}
+	})
+
+	t.Run("existence check alternatives", func(t *testing.T) {
+		tool := &config.Tool{
+			Name:         "go",
+			Alternatives: []config.Alternative{{CLI: "nonexistent-tool-xyz"}, {CLI: "go"}},
+		}
+
+		result := Check(tool)
+
+		if result.Status != StatusPass {
+			t.Fatalf("expected StatusPass, got %v (error: %v)", result.Status, result.Error)
+		}
+		if result.Path == "" {
+			t.Error("expected path of selected alternative to be set")
+		}
+	})
+
+	t.Run("fails when no alternative satisfies", func(t *testing.T) {
+		tool := &config.Tool{
+			Name:    "go toolchain",
+			CLI:     "nonexistent-tool-xyz",
+			Version: ">=999.0.0",
+			Alternatives: []config.Alternative{
+				{CLI: "go", VersionArg: "version"},
+			},
+		}
+
+		result := Check(tool)
+
+		if result.Status != StatusFail {
+			t.Errorf("expected StatusFail, got %v", result.Status)
+		}
+		if result.Selected != nil {
+			t.Errorf("expected no selection, got %q", result.Selected.CLI)
+		}
+		if result.Error == nil {
+			t.Fatal("expected error to be set")
+		}
+		for _, cli := range []string{"nonexistent-tool-xyz", "go"} {
+			if !strings.Contains(result.Error.Error(), cli) {
+				t.Errorf("expected error to mention %q, got %q", cli, result.Error)
+			}
+		}
+	})
+
+	t.Run("optional when no alternative satisfies", func(t *testing.T) {
+		tool := &config.Tool{
+			Name:         "runtime",
+			Optional:     true,
+			Alternatives: []config.Alternative{{CLI: "nonexistent-tool-xyz"}},
+		}
+
+		result := Check(tool)
+
+		if result.Status != StatusOptionalMissing {
+			t.Errorf("expected StatusOptionalMissing, got %v", result.Status)
+		}
+	})
+}
@@ -0,0 +1,221 @@
+package checker
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// Binary formats reported by inspectBinary.
+const (
+	formatELF   = "elf"
+	formatMachO = "mach-o"
+)
+
+// BinaryInfo describes an executable's headers.
+type BinaryInfo struct {
+	Format string   // "elf" or "mach-o"
+	Archs  []string // GOARCH-style names; universal Mach-O binaries have several
+	Static bool     // no dynamic loader or shared library dependencies
+	GLIBC  string   // highest GLIBC symbol version required, if any (e.g. "2.34")
+}
+
+// archAliases maps common uname-style architecture names to GOARCH names.
+var archAliases = map[string]string{
+	"x86_64":  "amd64",
+	"x64":     "amd64",
+	"aarch64": "arm64",
+	"i386":    "386",
+	"i686":    "386",
+}
+
+// checkBinary inspects the resolved binary when the tool constrains its
+// architecture or linking, failing the result on a mismatch.
+func checkBinary(tool *config.Tool, result *Result) {
+	if tool.Arch == "" && tool.Static == nil {
+		return
+	}
+
+	info, err := inspectBinary(result.Path)
+	if err == nil {
+		result.Binary = info
+		err = validateBinary(tool, info)
+	}
+	if err == nil {
+		return
+	}
+
+	result.Status = StatusFail
+	if tool.Optional {
+		result.Status = StatusOptionalMissing
+	}
+	result.Error = err
+}
+
+// validateBinary checks inspected headers against the tool's arch and static settings.
+func validateBinary(tool *config.Tool, info *BinaryInfo) error {
+	if tool.Arch != "" {
+		want := normalizeArch(tool.Arch)
+		if want == "native" {
+			want = runtime.GOARCH
+		}
+		if !slices.Contains(info.Archs, want) {
+			return fmt.Errorf("binary architecture is %s, expected %s", strings.Join(info.Archs, ", "), want)
+		}
+	}
+
+	if tool.Static != nil && *tool.Static != info.Static {
+		if info.Static {
+			return errors.New("binary is statically linked, expected dynamic linking")
+		}
+		return errors.New("binary is dynamically linked, expected static linking")
+	}
+
+	return nil
+}
+
+// inspectBinary reads the ELF or Mach-O headers of the executable at path.
+func inspectBinary(path string) (*BinaryInfo, error) {
+	if file, err := elf.Open(path); err == nil {
+		defer func() {
+			_ = file.Close()
+		}()
+		return inspectELF(file), nil
+	}
+
+	if file, err := macho.Open(path); err == nil {
+		defer func() {
+			_ = file.Close()
+		}()
+		return inspectMachO(file), nil
+	}
+
+	if fat, err := macho.OpenFat(path); err == nil {
+		defer func() {
+			_ = fat.Close()
+		}()
+
+		info := &BinaryInfo{Format: formatMachO}
+		for _, arch := range fat.Arches {
+			slice := inspectMachO(arch.File)
+			info.Archs = append(info.Archs, slice.Archs...)
+			info.Static = slice.Static
+		}
+		return info, nil
+	}
+
+	return nil, fmt.Errorf("cannot inspect %s: not an ELF or Mach-O executable", path)
+}
+
+// inspectELF reports an ELF binary's architecture, linking and glibc requirement.
+func inspectELF(file *elf.File) *BinaryInfo {
+	info := &BinaryInfo{Format: formatELF, Archs: []string{elfArch(file)}}
+
+	interpreted := slices.ContainsFunc(file.Progs, func(prog *elf.Prog) bool {
+		return prog.Type == elf.PT_INTERP
+	})
+	libraries, _ := file.ImportedLibraries()
+	info.Static = !interpreted && len(libraries) == 0
+
+	// Static binaries have no dynamic symbol table, so the error is expected
+	symbols, _ := file.ImportedSymbols()
+	for _, symbol := range symbols {
+		version, ok := strings.CutPrefix(symbol.Version, "GLIBC_")
+		if ok && compareDottedVersions(version, info.GLIBC) > 0 {
+			info.GLIBC = version
+		}
+	}
+
+	return info
+}
+
+// inspectMachO reports a single-architecture Mach-O binary's architecture and linking.
+func inspectMachO(file *macho.File) *BinaryInfo {
+	libraries, _ := file.ImportedLibraries()
+	return &BinaryInfo{
+		Format: formatMachO,
+		Archs:  []string{machoArch(file.Cpu)},
+		Static: len(libraries) == 0,
+	}
+}
+
+// elfArch converts an ELF machine type to a GOARCH-style name.
+func elfArch(file *elf.File) string {
+	switch file.Machine {
+	case elf.EM_X86_64:
+		return "amd64"
+	case elf.EM_AARCH64:
+		return "arm64"
+	case elf.EM_386:
+		return "386"
+	case elf.EM_ARM:
+		return "arm"
+	case elf.EM_RISCV:
+		return "riscv64"
+	case elf.EM_S390:
+		return "s390x"
+	case elf.EM_PPC64:
+		if file.ByteOrder == binary.LittleEndian {
+			return "ppc64le"
+		}
+		return "ppc64"
+	default:
+		return strings.ToLower(strings.TrimPrefix(file.Machine.String(), "EM_"))
+	}
+}
+
+// machoArch converts a Mach-O CPU type to a GOARCH-style name.
+func machoArch(cpu macho.Cpu) string {
+	switch cpu {
+	case macho.CpuAmd64:
+		return "amd64"
+	case macho.CpuArm64:
+		return "arm64"
+	case macho.Cpu386:
+		return "386"
+	case macho.CpuArm:
+		return "arm"
+	case macho.CpuPpc64:
+		return "ppc64"
+	case macho.CpuPpc:
+		return "ppc"
+	default:
+		return strings.ToLower(strings.TrimPrefix(cpu.String(), "Cpu"))
+	}
+}
+
+// normalizeArch lowercases an architecture name and maps uname-style aliases to GOARCH names.
+func normalizeArch(arch string) string {
+	arch = strings.ToLower(strings.TrimSpace(arch))
+	if alias, ok := archAliases[arch]; ok {
+		return alias
+	}
+	return arch
+}
+
+// compareDottedVersions compares numeric dotted versions like "2.17" and "2.34".
+// An empty version sorts before everything else.
+func compareDottedVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range max(len(as), len(bs)) {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return len(as) - len(bs)
+}
@@ -0,0 +1,192 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+func TestInspectBinary(t *testing.T) {
+	t.Run("reads the test binary's headers", func(t *testing.T) {
+		if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+			t.Skip("test binary is neither ELF nor Mach-O")
+		}
+
+		executable, err := os.Executable()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := inspectBinary(executable)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expectedFormat := formatELF
+		if runtime.GOOS == "darwin" {
+			expectedFormat = formatMachO
+		}
+		if info.Format != expectedFormat {
+			t.Errorf("expected format %s, got %s", expectedFormat, info.Format)
+		}
+		if !slices.Contains(info.Archs, runtime.GOARCH) {
+			t.Errorf("expected archs to contain %s, got %v", runtime.GOARCH, info.Archs)
+		}
+	})
+
+	t.Run("rejects scripts", func(t *testing.T) {
+		script := writeFakeTool(t, t.TempDir(), "tool", "1.0.0")
+
+		if _, err := inspectBinary(script); err == nil || !strings.Contains(err.Error(), "not an ELF or Mach-O") {
+			t.Errorf("expected inspection error, got %v", err)
+		}
+	})
+}
+
+func TestValidateBinary(t *testing.T) {
+	static, dynamic := true, false
+	amd64 := &BinaryInfo{Format: formatELF, Archs: []string{"amd64"}, Static: true}
+	universal := &BinaryInfo{Format: formatMachO, Archs: []string{"amd64", "arm64"}}
+	native := &BinaryInfo{Format: formatELF, Archs: []string{runtime.GOARCH}}
+
+	tests := []struct {
+		name        string
+		tool        config.Tool
+		info        *BinaryInfo
+		errContains string
+	}{
+		{
+			name: "arch matches",
+			tool: config.Tool{Arch: "amd64"},
+			info: amd64,
+		},
+		{
+			name: "arch alias matches",
+			tool: config.Tool{Arch: "x86_64"},
+			info: amd64,
+		},
+		{
+			name: "native arch matches",
+			tool: config.Tool{Arch: "native"},
+			info: native,
+		},
+		{
+			name: "universal binary contains arch",
+			tool: config.Tool{Arch: "arm64"},
+			info: universal,
+		},
+		{
+			name:        "arch mismatch",
+			tool:        config.Tool{Arch: "arm64"},
+			info:        amd64,
+			errContains: "architecture is amd64, expected arm64",
+		},
+		{
+			name: "static matches",
+			tool: config.Tool{Static: &static},
+			info: amd64,
+		},
+		{
+			name:        "expected static",
+			tool:        config.Tool{Static: &static},
+			info:        universal,
+			errContains: "dynamically linked",
+		},
+		{
+			name:        "expected dynamic",
+			tool:        config.Tool{Static: &dynamic},
+			info:        amd64,
+			errContains: "statically linked",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBinary(&tt.tool, tt.info)
+
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+func TestCompareDottedVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"2.34", "2.17", 1},
+		{"2.17", "2.34", -1},
+		{"2.3.4", "2.3", 1},
+		{"2.34", "2.34", 0},
+		{"2.2.5", "", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			got := compareDottedVersions(tt.a, tt.b)
+			if (got > 0) != (tt.expected > 0) || (got < 0) != (tt.expected < 0) {
+				t.Errorf("expected sign of %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCheckBinary(t *testing.T) {
+	t.Run("skips inspection without constraints", func(t *testing.T) {
+		script := writeFakeTool(t, t.TempDir(), "tool", "1.0.0")
+		tool := &config.Tool{CLI: "tool"}
+		result := &Result{Tool: tool, Status: StatusPass, Path: script}
+
+		checkBinary(tool, result)
+
+		if result.Status != StatusPass || result.Binary != nil {
+			t.Errorf("expected untouched pass, got %v with %+v", result.Status, result.Binary)
+		}
+	})
+
+	t.Run("fails when binary can't be inspected", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFakeTool(t, dir, "tool", "1.0.0")
+		t.Setenv("PATH", dir)
+
+		result := Check(&config.Tool{Name: "tool", CLI: "tool", Arch: "native"})
+
+		if result.Status != StatusFail {
+			t.Errorf("expected StatusFail, got %v", result.Status)
+		}
+	})
+
+	t.Run("passes for a native binary", func(t *testing.T) {
+		if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+			t.Skip("test binary is neither ELF nor Mach-O")
+		}
+
+		executable, err := os.Executable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("PATH", filepath.Dir(executable))
+
+		result := Check(&config.Tool{Name: "self", CLI: filepath.Base(executable), Arch: "native"})
+
+		if result.Status != StatusPass {
+			t.Errorf("expected StatusPass, got %v (error: %v)", result.Status, result.Error)
+		}
+		if result.Binary == nil {
+			t.Error("expected binary info to be recorded")
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package checker
+
+import (
+	"debug/buildinfo"
+	"fmt"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// readVersionOutput returns the text a tool's version is extracted from: the output
+// of its version command, or the main module version embedded in the Go binary at path.
+func readVersionOutput(tool *config.Tool, path string) (string, error) {
+	switch tool.VersionSource {
+	case "", config.VersionSourceCommand:
+		return executeVersionCommand(tool)
+	case config.VersionSourceBuildInfo:
+		return readBuildInfoVersion(path)
+	default:
+		return "", fmt.Errorf(
+			"unknown version source %q (expected %q or %q)",
+			tool.VersionSource, config.VersionSourceCommand, config.VersionSourceBuildInfo,
+		)
+	}
+}
+
+// readBuildInfoVersion reads the main module version from a Go binary's build info
+// without executing it, e.g. "v2.6.1 (github.com/golangci/golangci-lint/v2)".
+func readBuildInfoVersion(path string) (string, error) {
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Go build info from %s: %w", path, err)
+	}
+
+	// Binaries built from a local checkout report "(devel)" instead of a version
+	version := info.Main.Version
+	if version == "" || version == "(devel)" {
+		return "", fmt.Errorf("%s has no module version in its Go build info", path)
+	}
+
+	return fmt.Sprintf("%s (%s)", version, info.Main.Path), nil
+}
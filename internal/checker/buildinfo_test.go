@@ -0,0 +1,121 @@
+package checker
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// buildGoBinary builds a trivial Go program named name in dir. When tag is set the
+// module is committed to a git repository and tagged, so its build info records tag
+// as the main module version; otherwise the version is "(devel)".
+func buildGoBinary(t *testing.T, dir, name, tag string) string {
+	t.Helper()
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not on PATH")
+	}
+
+	src := t.TempDir()
+	writeTestFile(t, filepath.Join(src, "go.mod"), "module example.com/"+name+"\n\ngo 1.24\n")
+	writeTestFile(t, filepath.Join(src, "main.go"), "package main\n\nfunc main() {}\n")
+
+	run := func(name string, args ...string) {
+		t.Helper()
+		cmd := exec.Command(name, args...)
+		cmd.Dir = src
+		cmd.Env = append(os.Environ(), "GOFLAGS=", "GOWORK=off", "GOTOOLCHAIN=local")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%s %s: %v\n%s", name, strings.Join(args, " "), err, output)
+		}
+	}
+
+	if tag != "" {
+		if _, err := exec.LookPath("git"); err != nil {
+			t.Skip("git is not on PATH")
+		}
+		run("git", "init", "-q")
+		run("git", "-c", "user.name=chex", "-c", "user.email=chex@example.com", "add", "-A")
+		run("git", "-c", "user.name=chex", "-c", "user.email=chex@example.com", "commit", "-q", "-m", "init")
+		run("git", "tag", tag)
+	}
+
+	binary := filepath.Join(dir, name)
+	run(goBin, "build", "-o", binary, ".")
+	return binary
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadBuildInfoVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("reads the main module version", func(t *testing.T) {
+		binary := buildGoBinary(t, dir, "tagged", "v1.4.2")
+
+		output, err := readBuildInfoVersion(binary)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if output != "v1.4.2 (example.com/tagged)" {
+			t.Errorf("unexpected output %q", output)
+		}
+	})
+
+	t.Run("fails without a module version", func(t *testing.T) {
+		binary := buildGoBinary(t, dir, "devel", "")
+
+		if _, err := readBuildInfoVersion(binary); err == nil || !strings.Contains(err.Error(), "no module version") {
+			t.Errorf("expected missing version error, got %v", err)
+		}
+	})
+
+	t.Run("fails for non-Go binaries", func(t *testing.T) {
+		script := writeFakeTool(t, dir, "script", "1.0.0")
+
+		_, err := readBuildInfoVersion(script)
+		if err == nil || !strings.Contains(err.Error(), "failed to read Go build info") {
+			t.Errorf("expected build info error, got %v", err)
+		}
+	})
+}
+
+func TestReadVersionOutput(t *testing.T) {
+	t.Run("rejects unknown sources", func(t *testing.T) {
+		tool := &config.Tool{CLI: "go", VersionSource: "magic"}
+
+		if _, err := readVersionOutput(tool, ""); err == nil || !strings.Contains(err.Error(), "unknown version source") {
+			t.Errorf("expected unknown source error, got %v", err)
+		}
+	})
+
+	t.Run("checks version from build info without executing", func(t *testing.T) {
+		dir := t.TempDir()
+		buildGoBinary(t, dir, "linter", "v1.6.1")
+		t.Setenv("PATH", dir)
+
+		result := Check(&config.Tool{
+			Name:          "linter",
+			CLI:           "linter",
+			Version:       ">=1.6.0",
+			VersionSource: "buildinfo",
+		})
+
+		if result.Status != StatusPass {
+			t.Errorf("expected StatusPass, got %v (error: %v)", result.Status, result.Error)
+		}
+		if result.InstalledVersion != "1.6.1" {
+			t.Errorf("expected installed version 1.6.1, got %q", result.InstalledVersion)
+		}
+	})
+}
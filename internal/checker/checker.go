@@ -5,22 +5,34 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"os"
 	"os/exec"
 	"regexp"
+	"slices"
 	"strings"
-	"time"
+	"unicode"
 
-	"github.com/Masterminds/semver/v3"
 	"github.com/drape-io/chex/internal/config"
 )
 
-// Result represents the result of checking a single tool.
+// Result represents the result of checking a single tool or requirement.
 type Result struct {
+	Category         Category
 	Tool             *config.Tool
 	Status           Status
 	InstalledVersion string
 	Path             string
+	ShimPath         string      // mise/asdf shim that Path was resolved from, if any
+	SHA256           string      // checksum of the binary at Path, when verified
+	Binary           *BinaryInfo // headers of the binary at Path, when inspected
 	Output           string
+	Reason           string           // why the tool was skipped, or its version excluded by policy
+	Selected         *config.Tool     // alternative that satisfied the requirement
+	Checks           []*CommandResult // results of the tool's custom checks
+	Subcommands      []*Result        // results of the tool's subcommand and plugin checks
+	Installations    []Installation   // every match for the CLI on PATH, in PATH order
+	Warnings         []string         // problems that don't fail the check on their own
 	Error            error
 }
 
@@ -31,44 +43,120 @@ const (
 	StatusPass            Status = "pass"
 	StatusFail            Status = "fail"
 	StatusOptionalMissing Status = "optional_missing"
+	StatusSkipped         Status = "skipped"
+)
+
+// Category represents the kind of requirement a result belongs to.
+type Category string
+
+const (
+	CategoryTool    Category = "tool"
+	CategoryEnv     Category = "env"
+	CategoryFile    Category = "file"
+	CategoryService Category = "service"
 )
 
 // Check checks a single tool and returns the result.
 func Check(tool *config.Tool) *Result {
 	result := &Result{
-		Tool: tool,
+		Category: CategoryTool,
+		Tool:     tool,
 	}
 
-	// If no version specified, just check existence
-	if tool.Version == "" {
-		return checkExistence(tool, result)
+	// Skip tools whose `when` condition isn't met
+	if met, reason := evaluateCondition(tool.When); !met {
+		result.Status = StatusSkipped
+		result.Reason = reason
+		return result
 	}
 
-	// Version specified, check version
-	return checkVersion(tool, result)
+	// A definition chex couldn't load only fails the tool once it's required
+	if tool.ConfigError != nil {
+		result.Status = StatusFail
+		if tool.Optional {
+			result.Status = StatusOptionalMissing
+		}
+		result.Error = tool.ConfigError
+		return result
+	}
+
+	if len(tool.Alternatives) > 0 {
+		checkAlternatives(tool, result)
+	} else {
+		checkCandidate(tool, result)
+	}
+
+	// Report every installation so shadowed binaries explain surprising results
+	inspectInstallations(tool, result)
+
+	// Subcommands and custom checks only make sense once the tool itself is usable
+	if result.Status == StatusPass {
+		checkSubcommands(tool, result)
+	}
+	if result.Status == StatusPass {
+		runCommandChecks(tool, result)
+	}
+
+	return result
 }
 
-// checkExistence checks if a tool exists on PATH without executing it.
-func checkExistence(tool *config.Tool, result *Result) *Result {
-	path, err := exec.LookPath(tool.CLI)
+// checkCandidate checks a single CLI for existence or version, then checks where it
+// was resolved from and that it suits this machine. The binary's checksum is verified
+// before anything runs it, so a tampered binary is never executed.
+func checkCandidate(tool *config.Tool, result *Result) *Result {
+	path, err := resolveBinary(tool, result)
 	if err != nil {
 		result.Status = StatusFail
 		if tool.Optional {
 			result.Status = StatusOptionalMissing
 		}
-		result.Error = fmt.Errorf("%s: command not found", tool.CLI)
+		result.Error = err
 		return result
 	}
-
-	result.Status = StatusPass
 	result.Path = path
+
+	if !verifyChecksum(tool, result) {
+		return result
+	}
+
+	if tool.Version == "" {
+		// If no version specified, finding the binary is enough
+		result.Status = StatusPass
+	} else {
+		checkVersion(tool, result, path)
+	}
+
+	if result.Status == StatusPass {
+		checkPathConstraints(tool, result)
+	}
+	if result.Status == StatusPass {
+		checkBinary(tool, result)
+	}
+
 	return result
 }
 
-// checkVersion checks if a tool exists and matches the version constraint.
-func checkVersion(tool *config.Tool, result *Result) *Result {
-	// Execute command to get version
-	versionOutput, err := executeVersionCommand(tool)
+// resolveBinary finds the binary a tool's CLI runs, looking through version manager shims.
+func resolveBinary(tool *config.Tool, result *Result) (string, error) {
+	path, err := exec.LookPath(tool.CLI)
+	if err != nil {
+		return "", fmt.Errorf("%s: command not found", tool.CLI)
+	}
+	return resolveShim(tool, result, path)
+}
+
+// checkVersion checks that the tool's binary at path matches the version constraint.
+func checkVersion(tool *config.Tool, result *Result, path string) *Result {
+	// Run the resolved binary so shims can't trigger installs or fail on unset versions
+	command := tool
+	if result.ShimPath != "" {
+		resolved := *tool
+		resolved.CLI = path
+		command = &resolved
+	}
+
+	// Execute command (or read build info) to get version
+	versionOutput, err := readVersionOutput(command, path)
 	if err != nil {
 		result.Status = StatusFail
 		if tool.Optional {
@@ -80,8 +168,14 @@ func checkVersion(tool *config.Tool, result *Result) *Result {
 
 	result.Output = versionOutput
 
+	return evaluateVersion(tool, result, versionOutput)
+}
+
+// evaluateVersion extracts the version from command output and checks it
+// against the tool's version constraint.
+func evaluateVersion(tool *config.Tool, result *Result, versionOutput string) *Result {
 	// Extract version from output
-	version, err := extractVersion(versionOutput, tool.VersionPattern)
+	version, err := extractToolVersion(tool, versionOutput)
 	if err != nil {
 		result.Status = StatusFail
 		result.Error = fmt.Errorf("failed to extract version: %w", err)
@@ -90,22 +184,16 @@ func checkVersion(tool *config.Tool, result *Result) *Result {
 
 	result.InstalledVersion = version
 
-	// Parse and check version constraint
-	constraint, err := semver.NewConstraint(tool.Version)
-	if err != nil {
-		result.Status = StatusFail
-		result.Error = fmt.Errorf("invalid version constraint '%s': %w", tool.Version, err)
-		return result
-	}
-
-	installedVer, err := semver.NewVersion(version)
+	// Check the version constraint using the tool's version scheme
+	satisfied, reason, err := versionSatisfies(tool, version)
 	if err != nil {
 		result.Status = StatusFail
-		result.Error = fmt.Errorf("failed to parse installed version '%s': %w", version, err)
+		result.Error = err
 		return result
 	}
+	result.Reason = reason
 
-	if constraint.Check(installedVer) {
+	if satisfied {
 		result.Status = StatusPass
 	} else {
 		result.Status = StatusFail
@@ -118,14 +206,23 @@ func checkVersion(tool *config.Tool, result *Result) *Result {
 }
 
 // executeVersionCommand executes the tool with its version argument.
-func executeVersionCommand(tool *config.Tool) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// Any prefix arguments (such as a subcommand) are passed before the version argument.
+func executeVersionCommand(tool *config.Tool, prefix ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultCommandTimeout)
 	defer cancel()
 
-	// If version arg is specified, use it
-	if tool.VersionArg != "" {
-		args := strings.Fields(tool.VersionArg)
-		cmd := exec.CommandContext(ctx, tool.CLI, args...)
+	// In shell mode only the version arguments are shell syntax; quote the prefix
+	if tool.Shell && len(prefix) > 0 {
+		prefix = []string{config.JoinArgs(prefix)}
+	}
+
+	// If version args are specified, use them
+	if tool.VersionArg != "" || len(tool.VersionArgs) > 0 {
+		versionArgs, err := toolVersionArgs(tool)
+		if err != nil {
+			return "", fmt.Errorf("%s: invalid version_arg: %w", tool.CLI, err)
+		}
+		cmd := versionCommand(ctx, tool, append(slices.Clone(prefix), versionArgs...))
 		output, err := runCommand(cmd)
 		if err != nil {
 			return "", fmt.Errorf("%s: %w", tool.CLI, err)
@@ -145,7 +242,8 @@ func executeVersionCommand(tool *config.Tool) (string, error) {
 	}
 
 	for _, args := range commonVersionArgs {
-		cmd := exec.CommandContext(ctx, tool.CLI, args...)
+		args = append(slices.Clone(prefix), args...)
+		cmd := versionCommand(ctx, tool, args)
 		output, err := runCommand(cmd)
 
 		// If we got output with version-like content, use it (even if exit code was non-zero)
@@ -161,6 +259,70 @@ func executeVersionCommand(tool *config.Tool) (string, error) {
 	return "", fmt.Errorf("%s: failed to get version", tool.CLI)
 }
 
+// toolVersionArgs returns the arguments that make the tool print its version. In
+// shell mode version_arg is passed to the shell as written.
+func toolVersionArgs(tool *config.Tool) ([]string, error) {
+	switch {
+	case len(tool.VersionArgs) > 0 && tool.Shell:
+		return []string{config.JoinArgs(tool.VersionArgs)}, nil
+	case len(tool.VersionArgs) > 0:
+		return slices.Clone(tool.VersionArgs), nil
+	case tool.Shell:
+		return []string{tool.VersionArg}, nil
+	default:
+		return config.SplitArgs(tool.VersionArg)
+	}
+}
+
+// versionCommand builds the command that runs the tool with args, through sh -c
+// for tools that opt into the shell. The shell gets the CLI quoted and args as
+// written, so callers quote any argument the shell mustn't interpret.
+func versionCommand(ctx context.Context, tool *config.Tool, args []string) *exec.Cmd {
+	var cmd *exec.Cmd
+	if tool.Shell {
+		line := strings.Join(append([]string{config.JoinArgs([]string{tool.CLI})}, args...), " ")
+		cmd = exec.CommandContext(ctx, "sh", "-c", line)
+	} else {
+		cmd = exec.CommandContext(ctx, tool.CLI, args...)
+	}
+	applyToolEnv(cmd, tool)
+	return cmd
+}
+
+// applyToolEnv runs cmd in the tool's working directory, with the tool's cleared
+// variables removed from the environment and its own variables added.
+func applyToolEnv(cmd *exec.Cmd, tool *config.Tool) {
+	cmd.Dir = tool.Workdir
+	if len(tool.Env) == 0 && len(tool.ClearEnv) == 0 {
+		return
+	}
+
+	// Non-nil even when every variable is cleared: a nil Env inherits the environment
+	env := []string{}
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		if _, overridden := tool.Env[name]; overridden || slices.Contains(tool.ClearEnv, name) {
+			continue
+		}
+		env = append(env, entry)
+	}
+	for _, name := range slices.Sorted(maps.Keys(tool.Env)) {
+		env = append(env, name+"="+tool.Env[name])
+	}
+	cmd.Env = env
+}
+
+// toolEnv returns the value of an environment variable as the tool's commands see it.
+func toolEnv(tool *config.Tool, name string) string {
+	if value, ok := tool.Env[name]; ok {
+		return value
+	}
+	if slices.Contains(tool.ClearEnv, name) {
+		return ""
+	}
+	return os.Getenv(name)
+}
+
 // looksLikeVersionOutput checks if output looks like version information.
 func looksLikeVersionOutput(output string) bool {
 	// Check if output contains version-like patterns
@@ -229,6 +391,18 @@ func runCommand(cmd *exec.Cmd) (string, error) {
 	return output, nil
 }
 
+var (
+	// defaultVersionRe finds X.Y.Z or X.Y, keeping any -prerelease and +build
+	// metadata attached to X.Y.Z
+	defaultVersionRe = regexp.MustCompile(`\d+\.\d+(\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?)?`)
+
+	// suffixRe splits X.Y.Z-suffix+build into its parts
+	suffixRe = regexp.MustCompile(`^(\d+\.\d+\.\d+)-([0-9A-Za-z.-]+)(\+.*)?$`)
+
+	// numericPrereleaseRe matches numeric prereleases such as 0.3.7
+	numericPrereleaseRe = regexp.MustCompile(`^\d+(\.\d+)+$`)
+)
+
 // extractVersion extracts a version string from command output.
 func extractVersion(output, pattern string) (string, error) {
 	if pattern != "" {
@@ -256,16 +430,27 @@ func extractVersion(output, pattern string) (string, error) {
 		}
 
 		// Try to find version pattern: X.Y.Z or X.Y
-		versionRe := regexp.MustCompile(`\d+\.\d+(\.\d+)?`)
-		match := versionRe.FindString(line)
+		match := defaultVersionRe.FindString(line)
 		if match != "" {
-			return match, nil
+			return dropPackagingRevision(match), nil
 		}
 	}
 
 	return "", errors.New("no version found in output")
 }
 
+// dropPackagingRevision removes a distro packaging revision such as the -1ubuntu1 in
+// 2.34.1-1ubuntu1, which the default pattern would otherwise read as a prerelease.
+// Revisions start with a digit; prereleases start with a letter (beta, rc1, nightly)
+// or are dotted numbers (0.3.7).
+func dropPackagingRevision(version string) string {
+	m := suffixRe.FindStringSubmatch(version)
+	if m == nil || !unicode.IsDigit(rune(m[2][0])) || numericPrereleaseRe.MatchString(m[2]) {
+		return version
+	}
+	return m[1] + m[3]
+}
+
 // CheckAll checks multiple tools and returns their results.
 func CheckAll(tools map[string]*config.Tool, filter []string) []*Result {
 	var results []*Result
@@ -277,6 +462,7 @@ func CheckAll(tools map[string]*config.Tool, filter []string) []*Result {
 			if !exists {
 				// Tool not found in config
 				results = append(results, &Result{
+					Category: CategoryTool,
 					Tool: &config.Tool{
 						Name: name,
 						CLI:  name,
@@ -1,6 +1,9 @@
 package checker
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -74,6 +77,9 @@ func TestCheckVersion(t *testing.T) {
 		if result.Output == "" {
 			t.Error("expected output to be set")
 		}
+		if result.Path == "" {
+			t.Error("expected path to be set")
+		}
 	})
 
 	t.Run("fails for version mismatch", func(t *testing.T) {
@@ -109,6 +115,94 @@ func TestCheckVersion(t *testing.T) {
 	})
 }
 
+func TestExecuteVersionCommand(t *testing.T) {
+	workdir := t.TempDir()
+	spaced := writeFakeTool(t, filepath.Join(t.TempDir(), "my tools"), "fake tool", "fake 1.2.3")
+	t.Setenv("CHEX_CLEARED", "banner")
+	t.Setenv("CHEX_OVERRIDDEN", "old")
+
+	tests := []struct {
+		name     string
+		tool     config.Tool
+		expected string
+		err      string
+	}{
+		{
+			name:     "quoted version_arg",
+			tool:     config.Tool{CLI: "sh", VersionArg: `-c 'echo "tool 1.2.3"'`},
+			expected: "tool 1.2.3",
+		},
+		{
+			name:     "version_args",
+			tool:     config.Tool{CLI: "sh", VersionArgs: []string{"-c", "echo {{.Version}} 1.2.3"}},
+			expected: "{{.Version}} 1.2.3",
+		},
+		{
+			name:     "shell pipeline",
+			tool:     config.Tool{CLI: "echo", VersionArg: "tool 1.2.3 | tr a-z A-Z", Shell: true},
+			expected: "TOOL 1.2.3",
+		},
+		{
+			name:     "shell with version_args",
+			tool:     config.Tool{CLI: "echo", VersionArgs: []string{"it's", "1.2.3;"}, Shell: true},
+			expected: "it's 1.2.3;",
+		},
+		{
+			name:     "shell quotes the cli",
+			tool:     config.Tool{CLI: spaced, VersionArg: "| tr a-z A-Z", Shell: true},
+			expected: "FAKE 1.2.3",
+		},
+		{
+			name:     "shell quotes version_args",
+			tool:     config.Tool{CLI: "echo", VersionArgs: []string{"$HOME", "`id`", "1.2.3"}, Shell: true},
+			expected: "$HOME `id` 1.2.3",
+		},
+		{
+			name:     "operators without shell",
+			tool:     config.Tool{CLI: "echo", VersionArg: "1.2.3 | tr"},
+			expected: "1.2.3 | tr",
+		},
+		{
+			name: "env and clear_env",
+			tool: config.Tool{
+				CLI:        "sh",
+				VersionArg: `-c 'echo "${CHEX_CLEARED-unset} $CHEX_OVERRIDDEN $CHEX_ADDED"'`,
+				Env:        map[string]string{"CHEX_OVERRIDDEN": "new", "CHEX_ADDED": "1.2.3"},
+				ClearEnv:   []string{"CHEX_CLEARED"},
+			},
+			expected: "unset new 1.2.3",
+		},
+		{
+			name:     "workdir",
+			tool:     config.Tool{CLI: "pwd", VersionArg: "-P", Workdir: workdir},
+			expected: workdir,
+		},
+		{
+			name: "unterminated quote",
+			tool: config.Tool{CLI: "echo", VersionArg: "'1.2.3"},
+			err:  "invalid version_arg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := executeVersionCommand(&tt.tool)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeVersionCommand() error = %v", err)
+			}
+			if strings.TrimSpace(output) != tt.expected {
+				t.Errorf("expected output %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
+
 func TestLooksLikeVersionOutput(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -227,6 +321,27 @@ func TestExtractVersion(t *testing.T) {
 			expectedResult: "",
 			expectError:    true,
 		},
+		{
+			name:           "prerelease and build metadata",
+			output:         "node v23.0.0-nightly+homebrew",
+			pattern:        "",
+			expectedResult: "23.0.0-nightly+homebrew",
+			expectError:    false,
+		},
+		{
+			name:           "packaging revision dropped",
+			output:         "git version 2.34.1-1ubuntu1",
+			pattern:        "",
+			expectedResult: "2.34.1",
+			expectError:    false,
+		},
+		{
+			name:           "build metadata",
+			output:         "tool 2.1.0+homebrew",
+			pattern:        "",
+			expectedResult: "2.1.0+homebrew",
+			expectError:    false,
+		},
 		{
 			name:           "multiline version output",
 			output:         "Tool Name\nVersion 1.2.3\nMore info",
@@ -284,7 +399,7 @@ func TestCheckAll(t *testing.T) {
 				passCount++
 			case StatusFail:
 				failCount++
-			case StatusOptionalMissing:
+			case StatusOptionalMissing, StatusSkipped:
 				// Not counted in this test
 			}
 		}
@@ -341,5 +456,24 @@ func TestCheckAll(t *testing.T) {
 		if results[0].Error == nil {
 			t.Error("expected error for unknown tool")
 		}
+
+		if results[0].Category != CategoryTool {
+			t.Errorf("expected CategoryTool, got %q", results[0].Category)
+		}
 	})
 }
+
+func TestApplyToolEnvClearsEverything(t *testing.T) {
+	var names []string
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		names = append(names, name)
+	}
+	cmd := exec.Command("env")
+
+	applyToolEnv(cmd, &config.Tool{ClearEnv: names})
+
+	if cmd.Env == nil || len(cmd.Env) != 0 {
+		t.Errorf("expected an empty environment, got %v", cmd.Env)
+	}
+}
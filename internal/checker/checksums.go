@@ -0,0 +1,130 @@
+package checker
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// verifyChecksum fails the result when the resolved binary's SHA-256 doesn't match
+// the tool's sha256 or the entry for it in sha256_file. It reports whether the binary
+// is safe to run, which it is when no checksum is configured.
+func verifyChecksum(tool *config.Tool, result *Result) bool {
+	if !hasChecksum(tool) {
+		return true
+	}
+
+	err := compareChecksum(tool, result)
+	if err == nil {
+		return true
+	}
+
+	result.Status = StatusFail
+	if tool.Optional {
+		result.Status = StatusOptionalMissing
+	}
+	result.Error = err
+	return false
+}
+
+// hasChecksum reports whether the tool pins its binary to a checksum.
+func hasChecksum(tool *config.Tool) bool {
+	return tool.SHA256 != "" || tool.SHA256File != ""
+}
+
+// compareChecksum hashes the resolved binary and compares it to the expected checksum.
+func compareChecksum(tool *config.Tool, result *Result) error {
+	actual, err := hashFile(result.Path)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", result.Path, err)
+	}
+	result.SHA256 = actual
+
+	expected := tool.SHA256
+	if expected == "" {
+		expected, err = lookupChecksum(tool.SHA256File, filepath.Base(result.Path))
+		if err != nil {
+			return err
+		}
+	}
+
+	if !strings.EqualFold(actual, strings.TrimSpace(expected)) {
+		return fmt.Errorf("checksum mismatch for %s: got %s, expected %s", result.Path, actual, expected)
+	}
+
+	return nil
+}
+
+// hashFile returns the hex-encoded SHA-256 of the file at path.
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// lookupChecksum finds the checksum for name in a sha256sum-style file ("<hash>  <name>").
+// Entries with a directory prefix match on their base name.
+func lookupChecksum(path, name string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read checksums file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var exact, base []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		// A leading '*' marks binary mode in sha256sum output
+		entry := strings.TrimPrefix(fields[1], "*")
+		switch {
+		case entry == name:
+			exact = append(exact, fields[0])
+		case filepath.Base(entry) == name:
+			base = append(base, fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read checksums file: %w", err)
+	}
+
+	matches := exact
+	if len(matches) == 0 {
+		matches = base
+	}
+	slices.Sort(matches)
+	matches = slices.Compact(matches)
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no checksum for %s in %s", name, path)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%d different checksums for %s in %s", len(matches), name, path)
+	}
+}
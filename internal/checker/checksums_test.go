@@ -0,0 +1,160 @@
+package checker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+func TestVerifyChecksum(t *testing.T) {
+	tmpDir := t.TempDir()
+	binary := writeFakeTool(t, filepath.Join(tmpDir, "bin"), "kubectl", "v1.29.0")
+
+	content, err := os.ReadFile(binary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(content)
+	actual := hex.EncodeToString(sum[:])
+	other := strings.Repeat("0", 64)
+
+	writeChecksums := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name        string
+		tool        config.Tool
+		errContains string
+	}{
+		{
+			name: "no checksum configured",
+			tool: config.Tool{CLI: "kubectl"},
+		},
+		{
+			name: "inline checksum matches",
+			tool: config.Tool{CLI: "kubectl", SHA256: strings.ToUpper(actual)},
+		},
+		{
+			name:        "inline checksum mismatch",
+			tool:        config.Tool{CLI: "kubectl", SHA256: other},
+			errContains: "checksum mismatch",
+		},
+		{
+			name: "checksums file matches",
+			tool: config.Tool{
+				CLI:        "kubectl",
+				SHA256File: writeChecksums("exact.txt", other+"  helm\n"+actual+"  kubectl\n"),
+			},
+		},
+		{
+			name: "checksums file matches by base name",
+			tool: config.Tool{
+				CLI:        "kubectl",
+				SHA256File: writeChecksums("nested.txt", actual+" *linux-amd64/kubectl\n"),
+			},
+		},
+		{
+			name: "checksums file mismatch",
+			tool: config.Tool{
+				CLI:        "kubectl",
+				SHA256File: writeChecksums("mismatch.txt", other+"  kubectl\n"),
+			},
+			errContains: "checksum mismatch",
+		},
+		{
+			name: "checksums file has no entry",
+			tool: config.Tool{
+				CLI:        "kubectl",
+				SHA256File: writeChecksums("missing.txt", actual+"  helm\n"),
+			},
+			errContains: "no checksum for kubectl",
+		},
+		{
+			name: "checksums file is ambiguous",
+			tool: config.Tool{
+				CLI: "kubectl",
+				SHA256File: writeChecksums("ambiguous.txt",
+					actual+"  linux-amd64/kubectl\n"+other+"  linux-arm64/kubectl\n"),
+			},
+			errContains: "2 different checksums",
+		},
+		{
+			name:        "checksums file doesn't exist",
+			tool:        config.Tool{CLI: "kubectl", SHA256File: filepath.Join(tmpDir, "nope.txt")},
+			errContains: "failed to read checksums file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &Result{Tool: &tt.tool, Status: StatusPass, Path: binary}
+			verifyChecksum(&tt.tool, result)
+
+			if tt.errContains == "" {
+				if result.Status != StatusPass {
+					t.Errorf("expected StatusPass, got %v (error: %v)", result.Status, result.Error)
+				}
+				return
+			}
+
+			if result.Status != StatusFail {
+				t.Errorf("expected StatusFail, got %v", result.Status)
+			}
+			if result.Error == nil || !strings.Contains(result.Error.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, result.Error)
+			}
+		})
+	}
+
+	t.Run("check fails on tampered binary", func(t *testing.T) {
+		t.Setenv("PATH", filepath.Dir(binary))
+
+		result := Check(&config.Tool{Name: "kubectl", CLI: "kubectl", SHA256: other})
+
+		if result.Status != StatusFail {
+			t.Errorf("expected StatusFail, got %v", result.Status)
+		}
+		if result.SHA256 != actual {
+			t.Errorf("expected recorded checksum %s, got %s", actual, result.SHA256)
+		}
+	})
+}
+
+func TestChecksumVerifiedBeforeRunning(t *testing.T) {
+	tmpDir := t.TempDir()
+	binDir := filepath.Join(tmpDir, "bin")
+	marker := filepath.Join(tmpDir, "ran")
+	if err := os.MkdirAll(binDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\ntouch '" + marker + "'\necho 'tampered 1.0.0'\n"
+	if err := os.WriteFile(filepath.Join(binDir, "tampered"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir)
+
+	result := Check(&config.Tool{
+		Name:    "tampered",
+		CLI:     "tampered",
+		Version: ">=1.0.0",
+		SHA256:  strings.Repeat("0", 64),
+		Checks:  []config.CommandCheck{{Command: "tampered --check"}},
+	})
+
+	if result.Status != StatusFail || result.Error == nil || !strings.Contains(result.Error.Error(), "checksum mismatch") {
+		t.Errorf("expected checksum mismatch, got %v (error: %v)", result.Status, result.Error)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("expected the binary not to run when its checksum doesn't match")
+	}
+}
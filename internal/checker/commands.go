@@ -0,0 +1,131 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// defaultCommandTimeout bounds how long a single command may run.
+const defaultCommandTimeout = 5 * time.Second
+
+// CommandResult represents the result of a custom check command.
+type CommandResult struct {
+	Name     string
+	Command  string
+	Status   Status
+	ExitCode int
+	Output   string
+	Error    error
+}
+
+// runCommandChecks runs a tool's custom checks and fails the result if any of them fail.
+func runCommandChecks(tool *config.Tool, result *Result) {
+	for _, check := range tool.Checks {
+		commandResult := runCommandCheck(tool, check)
+		result.Checks = append(result.Checks, commandResult)
+
+		if commandResult.Status != StatusPass {
+			result.Status = StatusFail
+			if tool.Optional {
+				result.Status = StatusOptionalMissing
+			}
+		}
+	}
+}
+
+// runCommandCheck runs a single check command and compares its exit code and stdout.
+// It runs in the tool's working directory and environment, like the version command.
+func runCommandCheck(tool *config.Tool, check config.CommandCheck) *CommandResult {
+	result := &CommandResult{
+		Name:    check.Name,
+		Command: check.Command,
+		Status:  StatusFail,
+	}
+	if result.Name == "" {
+		result.Name = check.Command
+	}
+
+	if strings.TrimSpace(check.Command) == "" {
+		result.Error = errors.New("command is empty")
+		return result
+	}
+	args := []string{"sh", "-c", check.Command}
+	if !check.Shell {
+		var err error
+		if args, err = config.SplitArgs(check.Command); err != nil {
+			result.Error = fmt.Errorf("invalid command: %w", err)
+			return result
+		}
+	}
+
+	timeout := defaultCommandTimeout
+	if check.Timeout != "" {
+		parsed, err := time.ParseDuration(check.Timeout)
+		if err != nil {
+			result.Error = fmt.Errorf("invalid timeout '%s': %w", check.Timeout, err)
+			return result
+		}
+		timeout = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	applyToolEnv(cmd, tool)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	result.Output = strings.TrimSpace(stdout.String())
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.Error = fmt.Errorf("timed out after %s", timeout)
+		return result
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		result.Error = err
+		return result
+	}
+
+	if result.ExitCode != check.ExitCode {
+		result.Error = fmt.Errorf("exit code %d, expected %d", result.ExitCode, check.ExitCode)
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			result.Error = fmt.Errorf("%w: %s", result.Error, firstLine(msg))
+		}
+		return result
+	}
+
+	if check.StdoutPattern != "" {
+		re, err := regexp.Compile(check.StdoutPattern)
+		if err != nil {
+			result.Error = fmt.Errorf("invalid stdout pattern: %w", err)
+			return result
+		}
+		if !re.MatchString(result.Output) {
+			result.Error = fmt.Errorf("stdout did not match %s", check.StdoutPattern)
+			return result
+		}
+	}
+
+	result.Status = StatusPass
+	return result
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
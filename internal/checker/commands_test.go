@@ -0,0 +1,164 @@
+package checker
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+func TestRunCommandCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		check       config.CommandCheck
+		expected    Status
+		errContains string
+	}{
+		{
+			name:     "passes on expected exit code",
+			check:    config.CommandCheck{Command: "go env GOROOT"},
+			expected: StatusPass,
+		},
+		{
+			name:     "passes when stdout matches",
+			check:    config.CommandCheck{Command: "go env GOOS", StdoutPattern: `^\w+$`},
+			expected: StatusPass,
+		},
+		{
+			name:        "fails when stdout doesn't match",
+			check:       config.CommandCheck{Command: "go env GOOS", StdoutPattern: `^plan10$`},
+			expected:    StatusFail,
+			errContains: "stdout did not match",
+		},
+		{
+			name:     "passes on non-zero expected exit code",
+			check:    config.CommandCheck{Command: "go nonexistent-subcommand", ExitCode: 2},
+			expected: StatusPass,
+		},
+		{
+			name:        "fails on unexpected exit code",
+			check:       config.CommandCheck{Command: "go nonexistent-subcommand"},
+			expected:    StatusFail,
+			errContains: "exit code 2, expected 0",
+		},
+		{
+			name:        "fails when command is missing",
+			check:       config.CommandCheck{Command: "nonexistent-tool-xyz status"},
+			expected:    StatusFail,
+			errContains: "not found",
+		},
+		{
+			name:        "fails on timeout",
+			check:       config.CommandCheck{Command: "sleep 5", Timeout: "100ms"},
+			expected:    StatusFail,
+			errContains: "timed out",
+		},
+		{
+			name:     "passes quoted arguments",
+			check:    config.CommandCheck{Command: `sh -c 'test "$0" = "a b"' "a b"`},
+			expected: StatusPass,
+		},
+		{
+			name:        "fails on unterminated quote",
+			check:       config.CommandCheck{Command: `go env "GOOS`},
+			expected:    StatusFail,
+			errContains: "invalid command",
+		},
+		{
+			name:     "runs pipelines with shell",
+			check:    config.CommandCheck{Command: "go env GOOS | grep -q .", Shell: true},
+			expected: StatusPass,
+		},
+		{
+			name:        "fails on empty command",
+			check:       config.CommandCheck{Command: " ", Shell: true},
+			expected:    StatusFail,
+			errContains: "command is empty",
+		},
+		{
+			name:        "fails on invalid timeout",
+			check:       config.CommandCheck{Command: "go env", Timeout: "soon"},
+			expected:    StatusFail,
+			errContains: "invalid timeout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCommandCheck(&config.Tool{}, tt.check)
+
+			if result.Status != tt.expected {
+				t.Errorf("expected %v, got %v (error: %v)", tt.expected, result.Status, result.Error)
+			}
+			if tt.errContains != "" {
+				if result.Error == nil || !strings.Contains(result.Error.Error(), tt.errContains) {
+					t.Errorf("expected error containing %q, got %v", tt.errContains, result.Error)
+				}
+			}
+			if result.Name != tt.check.Command {
+				t.Errorf("expected name to default to command, got %q", result.Name)
+			}
+		})
+	}
+}
+
+func TestRunCommandCheckToolEnv(t *testing.T) {
+	workdir := t.TempDir()
+	t.Setenv("CHEX_CLEARED", "set")
+	tool := &config.Tool{
+		Env:      map[string]string{"CHEX_CHECK_VAR": "from-tool"},
+		ClearEnv: []string{"CHEX_CLEARED"},
+		Workdir:  workdir,
+	}
+	check := config.CommandCheck{
+		Command:       `echo "$(pwd) $CHEX_CHECK_VAR ${CHEX_CLEARED:-cleared}"`,
+		Shell:         true,
+		StdoutPattern: "^" + regexp.QuoteMeta(workdir) + " from-tool cleared$",
+	}
+
+	result := runCommandCheck(tool, check)
+
+	if result.Status != StatusPass {
+		t.Errorf("expected the check to run in the tool's workdir and env, got %q (error: %v)", result.Output, result.Error)
+	}
+}
+
+func TestCheckRunsCommandChecks(t *testing.T) {
+	t.Run("fails tool when a check fails", func(t *testing.T) {
+		tool := &config.Tool{
+			Name: "go",
+			CLI:  "go",
+			Checks: []config.CommandCheck{
+				{Name: "goroot", Command: "go env GOROOT"},
+				{Name: "broken", Command: "go nonexistent-subcommand"},
+			},
+		}
+
+		result := Check(tool)
+
+		if result.Status != StatusFail {
+			t.Errorf("expected StatusFail, got %v", result.Status)
+		}
+		if len(result.Checks) != 2 {
+			t.Fatalf("expected 2 check results, got %d", len(result.Checks))
+		}
+		if result.Checks[0].Status != StatusPass || result.Checks[1].Status != StatusFail {
+			t.Errorf("unexpected check statuses: %v, %v", result.Checks[0].Status, result.Checks[1].Status)
+		}
+	})
+
+	t.Run("skips checks when tool is missing", func(t *testing.T) {
+		tool := &config.Tool{
+			Name:   "missing",
+			CLI:    "nonexistent-tool-xyz",
+			Checks: []config.CommandCheck{{Command: "go env"}},
+		}
+
+		result := Check(tool)
+
+		if len(result.Checks) != 0 {
+			t.Errorf("expected no checks to run, got %d", len(result.Checks))
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// evaluateCondition reports whether a tool's `when` condition is met.
+// When it isn't, the returned reason explains why the tool was skipped.
+func evaluateCondition(cond *config.Condition) (bool, string) {
+	if cond == nil {
+		return true, ""
+	}
+
+	if cond.Env != "" {
+		value, set := os.LookupEnv(cond.Env)
+		if cond.Equals != "" {
+			if value != cond.Equals {
+				return false, fmt.Sprintf("%s is %q, not %q", cond.Env, value, cond.Equals)
+			}
+		} else if !set || value == "" {
+			return false, cond.Env + " is not set"
+		}
+	}
+
+	if cond.FileExists != "" {
+		if _, err := os.Stat(cond.FileExists); err != nil {
+			return false, cond.FileExists + " does not exist"
+		}
+	}
+
+	return true, ""
+}
@@ -0,0 +1,149 @@
+package checker
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	tmpDir := t.TempDir()
+	dockerfile := filepath.Join(tmpDir, "Dockerfile")
+	if err := os.WriteFile(dockerfile, []byte("FROM scratch\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("CHEX_TEST_CI", "true")
+
+	tests := []struct {
+		name     string
+		cond     *config.Condition
+		expected bool
+	}{
+		{
+			name:     "nil condition",
+			cond:     nil,
+			expected: true,
+		},
+		{
+			name:     "env equals",
+			cond:     &config.Condition{Env: "CHEX_TEST_CI", Equals: "true"},
+			expected: true,
+		},
+		{
+			name:     "env not equal",
+			cond:     &config.Condition{Env: "CHEX_TEST_CI", Equals: "false"},
+			expected: false,
+		},
+		{
+			name:     "env set",
+			cond:     &config.Condition{Env: "CHEX_TEST_CI"},
+			expected: true,
+		},
+		{
+			name:     "env unset",
+			cond:     &config.Condition{Env: "CHEX_TEST_UNSET_XYZ"},
+			expected: false,
+		},
+		{
+			name:     "file exists",
+			cond:     &config.Condition{FileExists: dockerfile},
+			expected: true,
+		},
+		{
+			name:     "directory exists",
+			cond:     &config.Condition{FileExists: tmpDir},
+			expected: true,
+		},
+		{
+			name:     "file missing",
+			cond:     &config.Condition{FileExists: filepath.Join(tmpDir, "infra")},
+			expected: false,
+		},
+		{
+			name: "all conditions must hold",
+			cond: &config.Condition{
+				Env:        "CHEX_TEST_CI",
+				Equals:     "true",
+				FileExists: filepath.Join(tmpDir, "infra"),
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			met, reason := evaluateCondition(tt.cond)
+			if met != tt.expected {
+				t.Errorf("expected %v, got %v (reason: %q)", tt.expected, met, reason)
+			}
+			if !met && reason == "" {
+				t.Error("expected a reason for an unmet condition")
+			}
+		})
+	}
+}
+
+func TestCheckSkipsUnmetCondition(t *testing.T) {
+	tool := &config.Tool{
+		Name: "hadolint",
+		CLI:  "nonexistent-tool-xyz",
+		When: &config.Condition{FileExists: filepath.Join(t.TempDir(), "Dockerfile")},
+	}
+
+	result := Check(tool)
+
+	if result.Status != StatusSkipped {
+		t.Errorf("expected StatusSkipped, got %v", result.Status)
+	}
+	if result.Reason == "" {
+		t.Error("expected skip reason to be set")
+	}
+}
+
+func TestCheckConfigError(t *testing.T) {
+	configErr := errors.New(`.chex.toml:2:1: java.cli: undefined variable "JAVA_HOME"`)
+
+	tests := []struct {
+		name     string
+		tool     *config.Tool
+		expected Status
+	}{
+		{
+			name:     "fails the tool",
+			tool:     &config.Tool{Name: "java", CLI: "${JAVA_HOME}/bin/java", ConfigError: configErr},
+			expected: StatusFail,
+		},
+		{
+			name:     "optional tool",
+			tool:     &config.Tool{Name: "java", CLI: "${JAVA_HOME}/bin/java", Optional: true, ConfigError: configErr},
+			expected: StatusOptionalMissing,
+		},
+		{
+			name: "unmet condition",
+			tool: &config.Tool{
+				Name:        "java",
+				CLI:         "${JAVA_HOME}/bin/java",
+				When:        &config.Condition{FileExists: filepath.Join(t.TempDir(), "pom.xml")},
+				ConfigError: configErr,
+			},
+			expected: StatusSkipped,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Check(tt.tool)
+
+			if result.Status != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result.Status)
+			}
+			if tt.expected != StatusSkipped && !errors.Is(result.Error, configErr) {
+				t.Errorf("expected the config error, got %v", result.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package checker
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// CheckEnv checks a single environment variable requirement and returns the result.
+func CheckEnv(env *config.EnvRequirement) *Result {
+	result := &Result{
+		Category: CategoryEnv,
+		Tool:     requirementTool(env.Name, env.Variable, env.Optional, env.Message),
+	}
+
+	err := checkEnvValue(env, result)
+	if err != nil {
+		result.Status = StatusFail
+		if env.Optional {
+			result.Status = StatusOptionalMissing
+		}
+		result.Error = err
+		return result
+	}
+
+	result.Status = StatusPass
+	return result
+}
+
+// checkEnvValue validates the variable's value against the requirement.
+func checkEnvValue(env *config.EnvRequirement, result *Result) error {
+	value, set := os.LookupEnv(env.Variable)
+	if !set || value == "" {
+		return fmt.Errorf("%s is not set", env.Variable)
+	}
+
+	if env.Pattern != "" {
+		re, err := regexp.Compile(env.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("%s does not match %s", env.Variable, env.Pattern)
+		}
+	}
+
+	if env.IsDir {
+		info, err := os.Stat(value)
+		if err != nil || !info.IsDir() {
+			return fmt.Errorf("%s points to %s, which is not an existing directory", env.Variable, value)
+		}
+		result.Path = value
+	}
+
+	return nil
+}
+
+// CheckEnvAll checks multiple environment variable requirements, ordered by name.
+func CheckEnvAll(envs map[string]*config.EnvRequirement) []*Result {
+	return checkSorted(envs, CheckEnv)
+}
+
+// checkSorted checks every requirement in a map, ordered by name.
+func checkSorted[T any](requirements map[string]T, check func(T) *Result) []*Result {
+	results := make([]*Result, 0, len(requirements))
+	for _, name := range slices.Sorted(maps.Keys(requirements)) {
+		results = append(results, check(requirements[name]))
+	}
+	return results
+}
+
+// requirementTool describes a non-tool requirement as a config.Tool so that it can
+// flow through the same Result pipeline and formatters as tools. The target (variable,
+// path or address) is recorded as the CLI.
+func requirementTool(name, target string, optional bool, message string) *config.Tool {
+	return &config.Tool{
+		Name:     name,
+		CLI:      target,
+		Optional: optional,
+		Message:  message,
+	}
+}
@@ -0,0 +1,100 @@
+package checker
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+func TestCheckEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CHEX_TEST_GOPRIVATE", "github.com/drape-io/*")
+	t.Setenv("CHEX_TEST_JAVA_HOME", tmpDir)
+	t.Setenv("CHEX_TEST_MISSING_DIR", filepath.Join(tmpDir, "missing"))
+	t.Setenv("CHEX_TEST_EMPTY", "")
+
+	tests := []struct {
+		name     string
+		env      config.EnvRequirement
+		expected Status
+	}{
+		{
+			name:     "set variable",
+			env:      config.EnvRequirement{Variable: "CHEX_TEST_GOPRIVATE"},
+			expected: StatusPass,
+		},
+		{
+			name:     "unset variable",
+			env:      config.EnvRequirement{Variable: "CHEX_TEST_UNSET_XYZ"},
+			expected: StatusFail,
+		},
+		{
+			name:     "empty variable",
+			env:      config.EnvRequirement{Variable: "CHEX_TEST_EMPTY"},
+			expected: StatusFail,
+		},
+		{
+			name:     "optional unset variable",
+			env:      config.EnvRequirement{Variable: "CHEX_TEST_UNSET_XYZ", Optional: true},
+			expected: StatusOptionalMissing,
+		},
+		{
+			name:     "pattern matches",
+			env:      config.EnvRequirement{Variable: "CHEX_TEST_GOPRIVATE", Pattern: `github\.com/drape-io`},
+			expected: StatusPass,
+		},
+		{
+			name:     "pattern doesn't match",
+			env:      config.EnvRequirement{Variable: "CHEX_TEST_GOPRIVATE", Pattern: `^gitlab\.com`},
+			expected: StatusFail,
+		},
+		{
+			name:     "invalid pattern",
+			env:      config.EnvRequirement{Variable: "CHEX_TEST_GOPRIVATE", Pattern: `(`},
+			expected: StatusFail,
+		},
+		{
+			name:     "existing directory",
+			env:      config.EnvRequirement{Variable: "CHEX_TEST_JAVA_HOME", IsDir: true},
+			expected: StatusPass,
+		},
+		{
+			name:     "missing directory",
+			env:      config.EnvRequirement{Variable: "CHEX_TEST_MISSING_DIR", IsDir: true},
+			expected: StatusFail,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CheckEnv(&tt.env)
+
+			if result.Status != tt.expected {
+				t.Errorf("expected %v, got %v (error: %v)", tt.expected, result.Status, result.Error)
+			}
+			if result.Category != CategoryEnv {
+				t.Errorf("expected CategoryEnv, got %v", result.Category)
+			}
+			if tt.expected != StatusPass && result.Error == nil {
+				t.Error("expected error to be set")
+			}
+		})
+	}
+}
+
+func TestCheckEnvAll(t *testing.T) {
+	envs := map[string]*config.EnvRequirement{
+		"B_VAR": {Name: "B_VAR", Variable: "B_VAR"},
+		"A_VAR": {Name: "A_VAR", Variable: "A_VAR"},
+	}
+
+	results := CheckEnvAll(envs)
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Tool.Name != "A_VAR" || results[1].Tool.Name != "B_VAR" {
+		t.Errorf("expected results ordered by name, got %q, %q", results[0].Tool.Name, results[1].Tool.Name)
+	}
+}
@@ -0,0 +1,96 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// CheckFile checks a single file or directory requirement and returns the result.
+func CheckFile(file *config.FileRequirement) *Result {
+	result := &Result{
+		Category: CategoryFile,
+		Tool:     requirementTool(file.Name, file.Path, file.Optional, file.Message),
+	}
+
+	err := file.ConfigError
+	if err == nil {
+		err = checkFileState(file, result)
+	}
+	if err != nil {
+		result.Status = StatusFail
+		if file.Optional {
+			result.Status = StatusOptionalMissing
+		}
+		result.Error = err
+		return result
+	}
+
+	result.Status = StatusPass
+	return result
+}
+
+// checkFileState validates the file against each configured check in turn.
+func checkFileState(file *config.FileRequirement, result *Result) error {
+	info, err := os.Stat(file.Path)
+	if !file.Exists {
+		if err == nil {
+			return fmt.Errorf("%s exists but should not", file.Path)
+		}
+		return nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s does not exist", file.Path)
+	}
+	if err != nil {
+		return err
+	}
+
+	result.Path = file.Path
+
+	if file.Mode != "" {
+		want, err := strconv.ParseUint(file.Mode, 8, 32)
+		if err != nil {
+			return fmt.Errorf("invalid mode '%s': %w", file.Mode, err)
+		}
+		if got := info.Mode().Perm(); got != fs.FileMode(want).Perm() {
+			return fmt.Errorf("%s has mode %04o, expected %04o", file.Path, got, want)
+		}
+	}
+
+	if file.Contains != "" {
+		re, err := regexp.Compile(file.Contains)
+		if err != nil {
+			return fmt.Errorf("invalid contains pattern: %w", err)
+		}
+		data, err := os.ReadFile(file.Path)
+		if err != nil {
+			return err
+		}
+		if !re.Match(data) {
+			return fmt.Errorf("%s does not contain %s", file.Path, file.Contains)
+		}
+	}
+
+	if file.NewerThan != "" {
+		reference, err := os.Stat(file.NewerThan)
+		if err != nil {
+			return fmt.Errorf("cannot compare with %s: %w", file.NewerThan, err)
+		}
+		if !info.ModTime().After(reference.ModTime()) {
+			return fmt.Errorf("%s is older than %s", file.Path, file.NewerThan)
+		}
+	}
+
+	return nil
+}
+
+// CheckFileAll checks multiple file requirements, ordered by name.
+func CheckFileAll(files map[string]*config.FileRequirement) []*Result {
+	return checkSorted(files, CheckFile)
+}
@@ -0,0 +1,126 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+func TestCheckFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	npmrc := filepath.Join(tmpDir, ".npmrc")
+	if err := os.WriteFile(npmrc, []byte("registry=https://npm.example.com/\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	example := filepath.Join(tmpDir, ".env.example")
+	env := filepath.Join(tmpDir, ".env")
+	for _, path := range []string{example, env} {
+		if err := os.WriteFile(path, []byte("KEY=value\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(example, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	missing := filepath.Join(tmpDir, "missing")
+
+	tests := []struct {
+		name     string
+		file     config.FileRequirement
+		expected Status
+	}{
+		{
+			name:     "file exists",
+			file:     config.FileRequirement{Path: npmrc, Exists: true},
+			expected: StatusPass,
+		},
+		{
+			name:     "directory exists",
+			file:     config.FileRequirement{Path: tmpDir, Exists: true},
+			expected: StatusPass,
+		},
+		{
+			name:     "file missing",
+			file:     config.FileRequirement{Path: missing, Exists: true},
+			expected: StatusFail,
+		},
+		{
+			name:     "optional file missing",
+			file:     config.FileRequirement{Path: missing, Exists: true, Optional: true},
+			expected: StatusOptionalMissing,
+		},
+		{
+			name:     "file must be absent",
+			file:     config.FileRequirement{Path: missing, Exists: false},
+			expected: StatusPass,
+		},
+		{
+			name:     "file present but must be absent",
+			file:     config.FileRequirement{Path: npmrc, Exists: false},
+			expected: StatusFail,
+		},
+		{
+			name:     "mode matches",
+			file:     config.FileRequirement{Path: npmrc, Exists: true, Mode: "0600"},
+			expected: StatusPass,
+		},
+		{
+			name:     "mode mismatch",
+			file:     config.FileRequirement{Path: npmrc, Exists: true, Mode: "0644"},
+			expected: StatusFail,
+		},
+		{
+			name:     "invalid mode",
+			file:     config.FileRequirement{Path: npmrc, Exists: true, Mode: "rw-------"},
+			expected: StatusFail,
+		},
+		{
+			name:     "contains matches",
+			file:     config.FileRequirement{Path: npmrc, Exists: true, Contains: `(?m)^registry=`},
+			expected: StatusPass,
+		},
+		{
+			name:     "contains doesn't match",
+			file:     config.FileRequirement{Path: npmrc, Exists: true, Contains: `(?m)^//npm.example.com/:_authToken=`},
+			expected: StatusFail,
+		},
+		{
+			name:     "newer than reference",
+			file:     config.FileRequirement{Path: env, Exists: true, NewerThan: example},
+			expected: StatusPass,
+		},
+		{
+			name:     "older than reference",
+			file:     config.FileRequirement{Path: example, Exists: true, NewerThan: env},
+			expected: StatusFail,
+		},
+		{
+			name:     "missing reference",
+			file:     config.FileRequirement{Path: env, Exists: true, NewerThan: missing},
+			expected: StatusFail,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CheckFile(&tt.file)
+
+			if result.Status != tt.expected {
+				t.Errorf("expected %v, got %v (error: %v)", tt.expected, result.Status, result.Error)
+			}
+			if result.Category != CategoryFile {
+				t.Errorf("expected CategoryFile, got %v", result.Category)
+			}
+			if tt.expected != StatusPass && result.Error == nil {
+				t.Error("expected error to be set")
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// Installation represents one match for a tool's CLI on PATH.
+type Installation struct {
+	Path      string
+	Version   string
+	ManagedBy string // version manager providing the binary ("mise", "asdf"), if any
+}
+
+// inspectInstallations records every match for the tool's CLI on PATH and warns when
+// the first match shadows the binary the tool's version manager would provide.
+func inspectInstallations(tool *config.Tool, result *Result) {
+	checked := tool
+	if result.Selected != nil {
+		checked = result.Selected
+	}
+
+	if checked.CLI == "" {
+		return
+	}
+
+	paths := findInstallations(checked.CLI)
+	if len(paths) == 0 {
+		return
+	}
+
+	for i, path := range paths {
+		installation := Installation{Path: path, ManagedBy: managedBy(path)}
+
+		// Only execute the other matches when a version is being checked anyway, and
+		// never when they can't be verified against the tool's checksum
+		if checked.Version != "" && !hasChecksum(checked) {
+			if i == 0 {
+				installation.Version = result.InstalledVersion
+			} else {
+				installation.Version = probeInstallationVersion(checked, path)
+			}
+		}
+
+		result.Installations = append(result.Installations, installation)
+	}
+
+	if warning := shadowWarning(tool.Source, result.Installations); warning != "" {
+		result.Warnings = append(result.Warnings, warning)
+	}
+}
+
+// findInstallations returns every executable matching cli across PATH, in PATH order.
+// Paths that resolve to the same file, such as /bin/ls and /usr/bin/ls on merged-/usr
+// systems, are reported once, under the first PATH entry.
+func findInstallations(cli string) []string {
+	if strings.ContainsRune(cli, filepath.Separator) {
+		if path, err := exec.LookPath(cli); err == nil {
+			return []string{path}
+		}
+		return nil
+	}
+
+	var paths []string
+	var files []os.FileInfo
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		path, err := exec.LookPath(filepath.Join(dir, cli))
+		if err != nil || slices.Contains(paths, path) {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || slices.ContainsFunc(files, func(seen os.FileInfo) bool { return os.SameFile(seen, info) }) {
+			continue
+		}
+		paths = append(paths, path)
+		files = append(files, info)
+	}
+	return paths
+}
+
+// probeInstallationVersion runs a specific installation to get its version.
+func probeInstallationVersion(tool *config.Tool, path string) string {
+	// Run the binary behind a shim rather than the shim itself
+	if manager := shimManager(path); manager != "" {
+		target, err := whichManaged(manager, filepath.Base(path), tool)
+		if err != nil {
+			return ""
+		}
+		path = target
+	}
+
+	probe := *tool
+	probe.CLI = path
+
+	output, err := readVersionOutput(&probe, path)
+	if err != nil {
+		return ""
+	}
+	version, err := extractToolVersion(tool, output)
+	if err != nil {
+		return ""
+	}
+	return version
+}
+
+// shadowWarning describes when the first installation isn't the one the tool's
+// declared source (mise/asdf) would provide.
+func shadowWarning(source string, installations []Installation) string {
+	managers := sourceManagers(source)
+	if len(managers) == 0 || len(installations) == 0 {
+		return ""
+	}
+
+	active := installations[0]
+	if slices.Contains(managers, active.ManagedBy) {
+		return ""
+	}
+
+	for _, installation := range installations[1:] {
+		if slices.Contains(managers, installation.ManagedBy) {
+			return fmt.Sprintf(
+				"%s shadows the %s-managed %s; check the order of your PATH",
+				active.Path, installation.ManagedBy, installation.Path,
+			)
+		}
+	}
+
+	return fmt.Sprintf(
+		"%s is not provided by %s; run `%s install` or activate it in your shell",
+		active.Path, strings.Join(managers, " or "), managers[0],
+	)
+}
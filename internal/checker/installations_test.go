@@ -0,0 +1,169 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// writeFakeTool writes an executable script named name into dir that prints output.
+func writeFakeTool(t *testing.T, dir, name, output string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	script := "#!/bin/sh\necho '" + output + "'\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFindInstallations(t *testing.T) {
+	tmpDir := t.TempDir()
+	first := writeFakeTool(t, filepath.Join(tmpDir, "first"), "faketool", "faketool 1.0.0")
+	second := writeFakeTool(t, filepath.Join(tmpDir, "second"), "faketool", "faketool 2.0.0")
+	empty := filepath.Join(tmpDir, "empty")
+	if err := os.MkdirAll(empty, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PATH", strings.Join([]string{
+		filepath.Dir(first), empty, filepath.Dir(second), filepath.Dir(first),
+	}, string(os.PathListSeparator)))
+
+	paths := findInstallations("faketool")
+
+	if len(paths) != 2 {
+		t.Fatalf("expected 2 installations, got %v", paths)
+	}
+	if paths[0] != first || paths[1] != second {
+		t.Errorf("expected installations in PATH order, got %v", paths)
+	}
+
+	if paths := findInstallations("nonexistent-tool-xyz"); len(paths) != 0 {
+		t.Errorf("expected no installations, got %v", paths)
+	}
+}
+
+func TestInspectInstallations(t *testing.T) {
+	tmpDir := t.TempDir()
+	miseDir := filepath.Join(tmpDir, "mise")
+	t.Setenv("MISE_DATA_DIR", miseDir)
+
+	system := writeFakeTool(t, filepath.Join(tmpDir, "usr", "bin"), "faketool", "faketool 1.0.0")
+	managed := writeFakeTool(
+		t, filepath.Join(miseDir, "installs", "faketool", "2.0.0", "bin"), "faketool", "faketool 2.0.0",
+	)
+
+	t.Run("warns when an unmanaged binary shadows the managed one", func(t *testing.T) {
+		t.Setenv("PATH", filepath.Dir(system)+string(os.PathListSeparator)+filepath.Dir(managed))
+
+		result := Check(&config.Tool{
+			Name:    "faketool",
+			CLI:     "faketool",
+			Version: ">=2.0.0",
+			Source:  "mise:mise.toml",
+		})
+
+		if result.Status != StatusFail {
+			t.Errorf("expected StatusFail from the shadowing binary, got %v", result.Status)
+		}
+		if len(result.Installations) != 2 {
+			t.Fatalf("expected 2 installations, got %d", len(result.Installations))
+		}
+		if result.Installations[0].Version != "1.0.0" || result.Installations[1].Version != "2.0.0" {
+			t.Errorf("expected versions of every installation, got %+v", result.Installations)
+		}
+		if result.Installations[1].ManagedBy != "mise" {
+			t.Errorf("expected second installation to be managed by mise, got %q", result.Installations[1].ManagedBy)
+		}
+		if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "shadows") {
+			t.Errorf("expected shadow warning, got %v", result.Warnings)
+		}
+	})
+
+	t.Run("no warning when managed binary comes first", func(t *testing.T) {
+		t.Setenv("PATH", filepath.Dir(managed)+string(os.PathListSeparator)+filepath.Dir(system))
+
+		result := Check(&config.Tool{
+			Name:    "faketool",
+			CLI:     "faketool",
+			Version: ">=2.0.0",
+			Source:  "mise:mise.toml",
+		})
+
+		if result.Status != StatusPass {
+			t.Errorf("expected StatusPass, got %v (error: %v)", result.Status, result.Error)
+		}
+		if len(result.Warnings) != 0 {
+			t.Errorf("expected no warnings, got %v", result.Warnings)
+		}
+	})
+
+	t.Run("no warning for tools from the main config", func(t *testing.T) {
+		t.Setenv("PATH", filepath.Dir(system)+string(os.PathListSeparator)+filepath.Dir(managed))
+
+		result := Check(&config.Tool{Name: "faketool", CLI: "faketool", Source: "config"})
+
+		if len(result.Installations) != 2 {
+			t.Fatalf("expected 2 installations, got %d", len(result.Installations))
+		}
+		if result.Installations[1].Version != "" {
+			t.Error("expected existence checks not to execute other installations")
+		}
+		if len(result.Warnings) != 0 {
+			t.Errorf("expected no warnings, got %v", result.Warnings)
+		}
+	})
+}
+
+func TestShadowWarning(t *testing.T) {
+	t.Run("warns when source manager provides nothing", func(t *testing.T) {
+		warning := shadowWarning("tool-versions:.tool-versions", []Installation{{Path: "/usr/bin/node"}})
+
+		if !strings.Contains(warning, "not provided by asdf or mise") {
+			t.Errorf("unexpected warning: %q", warning)
+		}
+	})
+
+	t.Run("accepts either manager for .tool-versions", func(t *testing.T) {
+		warning := shadowWarning("tool-versions:.tool-versions", []Installation{
+			{Path: "/home/dev/.local/share/mise/installs/node/20/bin/node", ManagedBy: "mise"},
+		})
+
+		if warning != "" {
+			t.Errorf("expected no warning, got %q", warning)
+		}
+	})
+}
+
+func TestFindInstallationsSameFile(t *testing.T) {
+	// A merged-/usr layout, where /bin is a symlink to /usr/bin, and a symlinked binary
+	root := t.TempDir()
+	usrBin := filepath.Join(root, "usr", "bin")
+	binary := writeFakeTool(t, usrBin, "faketool", "faketool 1.0.0")
+	if err := os.Symlink(usrBin, filepath.Join(root, "bin")); err != nil {
+		t.Fatal(err)
+	}
+	linkDir := filepath.Join(root, "local")
+	if err := os.MkdirAll(linkDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(binary, filepath.Join(linkDir, "faketool")); err != nil {
+		t.Fatal(err)
+	}
+
+	bin := filepath.Join(root, "bin")
+	t.Setenv("PATH", strings.Join([]string{bin, usrBin, linkDir}, string(os.PathListSeparator)))
+
+	paths := findInstallations("faketool")
+
+	if len(paths) != 1 || paths[0] != filepath.Join(bin, "faketool") {
+		t.Errorf("expected only the first PATH entry, got %v", paths)
+	}
+}
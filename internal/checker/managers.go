@@ -0,0 +1,134 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// managerDataDir returns the data directory of a version manager, honoring the
+// same environment variables the manager itself does.
+func managerDataDir(manager string) string {
+	home, _ := os.UserHomeDir()
+
+	switch manager {
+	case config.ManagerMise:
+		if dir := os.Getenv("MISE_DATA_DIR"); dir != "" {
+			return dir
+		}
+		if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+			return filepath.Join(dir, "mise")
+		}
+		return filepath.Join(home, ".local", "share", "mise")
+	case config.ManagerAsdf:
+		if dir := os.Getenv("ASDF_DATA_DIR"); dir != "" {
+			return dir
+		}
+		return filepath.Join(home, ".asdf")
+	}
+
+	return ""
+}
+
+// versionFiles returns the files the manager reads a project's versions from, in
+// order of precedence within a directory.
+func versionFiles(manager string) []string {
+	toolVersions := ".tool-versions"
+	if name := os.Getenv("ASDF_DEFAULT_TOOL_VERSIONS_FILENAME"); name != "" && manager == config.ManagerAsdf {
+		toolVersions = name
+	}
+	if manager == config.ManagerMise {
+		return []string{"mise.toml", ".mise.toml", toolVersions}
+	}
+	return []string{toolVersions}
+}
+
+// globalVersionFile returns the file the manager reads its global versions from.
+func globalVersionFile(manager string) string {
+	home, _ := os.UserHomeDir()
+
+	if manager == config.ManagerMise {
+		if dir := os.Getenv("MISE_CONFIG_DIR"); dir != "" {
+			return filepath.Join(dir, "config.toml")
+		}
+		if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+			return filepath.Join(dir, "mise", "config.toml")
+		}
+		return filepath.Join(home, ".config", "mise", "config.toml")
+	}
+	return filepath.Join(home, ".tool-versions")
+}
+
+// homebrewPrefixes returns the directories Homebrew installs into.
+func homebrewPrefixes() []string {
+	prefixes := []string{"/opt/homebrew", "/usr/local/Cellar", "/home/linuxbrew/.linuxbrew"}
+	if prefix := os.Getenv("HOMEBREW_PREFIX"); prefix != "" {
+		prefixes = append([]string{prefix}, prefixes...)
+	}
+	return prefixes
+}
+
+// managedBy returns the manager whose install or shim directory contains path, or an
+// empty string if the binary isn't managed. Symlinks (such as Homebrew's links into
+// its Cellar) are followed if the path itself isn't recognized.
+func managedBy(path string) string {
+	if manager := managedByPath(path); manager != "" {
+		return manager
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil && resolved != path {
+		return managedByPath(resolved)
+	}
+	return ""
+}
+
+// managedByPath matches path against the known manager directories without following symlinks.
+func managedByPath(path string) string {
+	for _, manager := range []string{config.ManagerMise, config.ManagerAsdf} {
+		dataDir := managerDataDir(manager)
+		for _, sub := range []string{"installs", "shims"} {
+			if isWithin(path, filepath.Join(dataDir, sub)) {
+				return manager
+			}
+		}
+	}
+
+	for _, prefix := range homebrewPrefixes() {
+		if isWithin(path, prefix) {
+			return config.ManagerHomebrew
+		}
+	}
+
+	return ""
+}
+
+// shimManager returns the version manager whose shim directory contains path, or
+// an empty string if path isn't a shim.
+func shimManager(path string) string {
+	for _, manager := range []string{config.ManagerMise, config.ManagerAsdf} {
+		if isWithin(path, filepath.Join(managerDataDir(manager), "shims")) {
+			return manager
+		}
+	}
+	return ""
+}
+
+// sourceManagers returns the version managers that would provide a tool declared
+// in the given source, or nil if the source isn't a version manager file.
+func sourceManagers(source string) []string {
+	switch {
+	case strings.HasPrefix(source, "mise:"):
+		return []string{config.ManagerMise}
+	case strings.HasPrefix(source, "tool-versions:"):
+		// Both asdf and mise read .tool-versions
+		return []string{config.ManagerAsdf, config.ManagerMise}
+	}
+	return nil
+}
+
+// isWithin reports whether path is inside dir.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,72 @@
+package checker
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestManagedBy(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("MISE_DATA_DIR", filepath.Join(tmpDir, "mise"))
+	t.Setenv("ASDF_DATA_DIR", filepath.Join(tmpDir, "asdf"))
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{
+			name:     "mise install",
+			path:     filepath.Join(tmpDir, "mise", "installs", "go", "1.25.4", "bin", "go"),
+			expected: "mise",
+		},
+		{
+			name:     "mise shim",
+			path:     filepath.Join(tmpDir, "mise", "shims", "go"),
+			expected: "mise",
+		},
+		{
+			name:     "asdf shim",
+			path:     filepath.Join(tmpDir, "asdf", "shims", "node"),
+			expected: "asdf",
+		},
+		{
+			name:     "system binary",
+			path:     "/usr/bin/go",
+			expected: "",
+		},
+		{
+			name:     "sibling directory",
+			path:     filepath.Join(tmpDir, "mise", "installs-old", "go"),
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := managedBy(tt.path); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestManagerDataDir(t *testing.T) {
+	t.Run("uses XDG_DATA_HOME for mise", func(t *testing.T) {
+		t.Setenv("MISE_DATA_DIR", "")
+		t.Setenv("XDG_DATA_HOME", "/data")
+
+		if got := managerDataDir("mise"); got != filepath.Join("/data", "mise") {
+			t.Errorf("unexpected mise data dir: %q", got)
+		}
+	})
+
+	t.Run("prefers MISE_DATA_DIR", func(t *testing.T) {
+		t.Setenv("MISE_DATA_DIR", "/opt/mise")
+		t.Setenv("XDG_DATA_HOME", "/data")
+
+		if got := managerDataDir("mise"); got != "/opt/mise" {
+			t.Errorf("unexpected mise data dir: %q", got)
+		}
+	})
+}
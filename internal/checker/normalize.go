@@ -0,0 +1,123 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// versionPreset is a built-in normalizer for a family of tools whose version output
+// the generic X.Y.Z pattern gets wrong.
+type versionPreset struct {
+	pattern      string
+	replacements []config.Replacement
+	coerce       bool
+}
+
+// versionPresets are the normalizers available through version_preset.
+var versionPresets = map[string]versionPreset{
+	// openjdk 21 2023-09-19, openjdk version "17.0.9", java version "1.8.0_392"
+	config.PresetJava: {
+		pattern: `(?i)(?:openjdk|java)(?: version)?\s+"?(\d+(?:\.\d+){0,2}(?:_\d+)?)`,
+		replacements: []config.Replacement{
+			// Legacy 1.8.0_392 is Java 8 update 392
+			{From: `^1\.(\d+)\.0_(\d+)$`, To: "$1.0.$2"},
+			{From: `^1\.(\d+)(?:\.0)?$`, To: "$1.0.0"},
+			{From: `_`, To: "+"},
+		},
+		coerce: true,
+	},
+	// Python 2.7.18, Python 2.7.18rc1, Python 2.7.18+ (Debian)
+	config.PresetPython2: {
+		pattern: `Python\s+(\d+\.\d+(?:\.\d+)?(?:(?:a|b|rc)\d+)?)`,
+		replacements: []config.Replacement{
+			{From: `(\d)(a|b|rc)(\d+)$`, To: "$1-$2.$3"},
+		},
+		coerce: true,
+	},
+	// ruby 2.7.8p225 (2023-03-30 revision 1f4d455848), ruby 3.4.0preview1
+	config.PresetRuby: {
+		pattern: `ruby\s+(\d+\.\d+\.\d+(?:p\d+|dev|preview\d+|rc\d+)?)`,
+		replacements: []config.Replacement{
+			// The patchlevel is build metadata; it doesn't change the release
+			{From: `p(\d+)$`, To: "+p$1"},
+			{From: `(\d)(dev|preview\d+|rc\d+)$`, To: "$1-$2"},
+		},
+	},
+}
+
+var shortVersionRe = regexp.MustCompile(`^(\d+)(\.\d+)?(\.\d+)?(.*)$`)
+
+// capturePattern returns the pattern used to capture a tool's version: its own
+// version_pattern, then its preset's, then its version scheme's default.
+func capturePattern(tool *config.Tool) (string, error) {
+	if tool.VersionPattern != "" {
+		return tool.VersionPattern, nil
+	}
+	if tool.VersionPreset != "" {
+		preset, err := lookupPreset(tool.VersionPreset)
+		if err != nil {
+			return "", err
+		}
+		return preset.pattern, nil
+	}
+	return schemePatterns[tool.VersionScheme], nil
+}
+
+// normalizeVersion applies the preset's and then the tool's replacements to a
+// captured version, and pads short versions when coercion is enabled.
+func normalizeVersion(tool *config.Tool, version string) (string, error) {
+	replacements := tool.VersionReplace
+	coerce := tool.VersionCoerce
+	if tool.VersionPreset != "" {
+		preset, err := lookupPreset(tool.VersionPreset)
+		if err != nil {
+			return "", err
+		}
+		replacements = slices.Concat(preset.replacements, replacements)
+		coerce = coerce || preset.coerce
+	}
+
+	for _, replacement := range replacements {
+		re, err := regexp.Compile(replacement.From)
+		if err != nil {
+			return "", fmt.Errorf("invalid version replacement %q: %w", replacement.From, err)
+		}
+		version = re.ReplaceAllString(version, replacement.To)
+	}
+
+	if coerce {
+		version = coerceVersion(version)
+	}
+	return version, nil
+}
+
+// coerceVersion pads a version to three numeric segments, keeping any suffix:
+// 21 -> 21.0.0, 1.8 -> 1.8.0, 21-ea -> 21.0.0-ea.
+func coerceVersion(version string) string {
+	m := shortVersionRe.FindStringSubmatch(version)
+	if m == nil {
+		return version
+	}
+
+	minor, patch := m[2], m[3]
+	if minor == "" {
+		minor = ".0"
+	}
+	if patch == "" {
+		patch = ".0"
+	}
+	return m[1] + minor + patch + m[4]
+}
+
+// lookupPreset returns the named built-in normalizer.
+func lookupPreset(name string) (versionPreset, error) {
+	preset, ok := versionPresets[strings.ToLower(name)]
+	if !ok {
+		return versionPreset{}, fmt.Errorf("unknown version preset %q (expected java, python2 or ruby)", name)
+	}
+	return preset, nil
+}
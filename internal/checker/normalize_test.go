@@ -0,0 +1,130 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+func TestNormalizeVersionPipeline(t *testing.T) {
+	tests := []struct {
+		name           string
+		tool           config.Tool
+		output         string
+		expectedResult string
+		errContains    string
+	}{
+		{
+			name:           "java feature release",
+			tool:           config.Tool{VersionPreset: "java"},
+			output:         "openjdk 21 2023-09-19\nOpenJDK Runtime Environment (build 21+35)",
+			expectedResult: "21.0.0",
+		},
+		{
+			name:           "java quoted version",
+			tool:           config.Tool{VersionPreset: "java"},
+			output:         `openjdk version "17.0.9" 2023-10-17`,
+			expectedResult: "17.0.9",
+		},
+		{
+			name:           "java legacy update",
+			tool:           config.Tool{VersionPreset: "java"},
+			output:         `java version "1.8.0_392"`,
+			expectedResult: "8.0.392",
+		},
+		{
+			name:           "python2 release",
+			tool:           config.Tool{VersionPreset: "python2"},
+			output:         "Python 2.7.18",
+			expectedResult: "2.7.18",
+		},
+		{
+			name:           "python2 release candidate",
+			tool:           config.Tool{VersionPreset: "python2"},
+			output:         "Python 2.7.18rc1",
+			expectedResult: "2.7.18-rc.1",
+		},
+		{
+			name:           "ruby patchlevel",
+			tool:           config.Tool{VersionPreset: "ruby"},
+			output:         "ruby 2.7.8p225 (2023-03-30 revision 1f4d455848) [x86_64-linux]",
+			expectedResult: "2.7.8+p225",
+		},
+		{
+			name:           "ruby preview",
+			tool:           config.Tool{VersionPreset: "ruby"},
+			output:         "ruby 3.4.0preview1 (2024-05-16 master 9d69619623) [x86_64-linux]",
+			expectedResult: "3.4.0-preview1",
+		},
+		{
+			name: "custom capture, replacement and coercion",
+			tool: config.Tool{
+				VersionPattern: `build (\d+_\d+)`,
+				VersionReplace: []config.Replacement{{From: "_", To: "."}},
+				VersionCoerce:  true,
+			},
+			output:         "tool build 4_2",
+			expectedResult: "4.2.0",
+		},
+		{
+			name: "tool replacements run after the preset's",
+			tool: config.Tool{
+				VersionPreset:  "ruby",
+				VersionReplace: []config.Replacement{{From: `\+p\d+$`, To: ""}},
+			},
+			output:         "ruby 2.7.8p225",
+			expectedResult: "2.7.8",
+		},
+		{
+			name:        "unknown preset",
+			tool:        config.Tool{VersionPreset: "cobol"},
+			output:      "1.0.0",
+			errContains: "unknown version preset",
+		},
+		{
+			name:        "invalid replacement",
+			tool:        config.Tool{VersionReplace: []config.Replacement{{From: "(", To: ""}}},
+			output:      "1.0.0",
+			errContains: "invalid version replacement",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := extractToolVersion(&tt.tool, tt.output)
+
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expectedResult {
+				t.Errorf("expected %q, got %q", tt.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestCoerceVersion(t *testing.T) {
+	tests := map[string]string{
+		"21":      "21.0.0",
+		"1.8":     "1.8.0",
+		"1.2.3":   "1.2.3",
+		"21-ea":   "21.0.0-ea",
+		"1.2.3.4": "1.2.3.4",
+		"latest":  "latest",
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			if got := coerceVersion(input); got != expected {
+				t.Errorf("expected %q, got %q", expected, got)
+			}
+		})
+	}
+}
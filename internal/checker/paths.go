@@ -0,0 +1,60 @@
+package checker
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// checkPathConstraints fails the result when the resolved binary comes from a
+// location the tool doesn't allow.
+func checkPathConstraints(tool *config.Tool, result *Result) {
+	err := validateResolvedPath(tool, result.Path)
+	if err == nil {
+		return
+	}
+
+	result.Status = StatusFail
+	if tool.Optional {
+		result.Status = StatusOptionalMissing
+	}
+	result.Error = err
+}
+
+// validateResolvedPath checks a resolved binary path against the tool's path_pattern
+// and must_be_managed_by settings.
+func validateResolvedPath(tool *config.Tool, path string) error {
+	if tool.PathPattern != "" {
+		re, err := regexp.Compile(tool.PathPattern)
+		if err != nil {
+			return fmt.Errorf("invalid path pattern: %w", err)
+		}
+
+		matched := re.MatchString(path)
+		if !matched {
+			// Allow patterns written against the symlink target, e.g. Homebrew's Cellar
+			if resolved, err := filepath.EvalSymlinks(path); err == nil {
+				matched = re.MatchString(resolved)
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s resolved to %s, which does not match %s", tool.CLI, path, tool.PathPattern)
+		}
+	}
+
+	if tool.ManagedBy != "" {
+		if manager := managedBy(path); manager != tool.ManagedBy {
+			if manager == "" {
+				manager = "no known manager"
+			}
+			return fmt.Errorf(
+				"%s resolved to %s, which is managed by %s instead of %s",
+				tool.CLI, path, manager, tool.ManagedBy,
+			)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package checker
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+func TestValidateResolvedPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("MISE_DATA_DIR", filepath.Join(tmpDir, "mise"))
+	t.Setenv("HOMEBREW_PREFIX", filepath.Join(tmpDir, "homebrew"))
+
+	managed := filepath.Join(tmpDir, "mise", "installs", "python", "3.12.0", "bin", "python")
+	system := "/usr/bin/python3"
+
+	cellar := writeFakeTool(t, filepath.Join(tmpDir, "homebrew", "Cellar", "jq", "1.7", "bin"), "jq", "jq-1.7")
+	link := filepath.Join(tmpDir, "bin", "jq")
+	if err := os.MkdirAll(filepath.Dir(link), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(cellar, link); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		tool        config.Tool
+		path        string
+		errContains string
+	}{
+		{
+			name: "no constraints",
+			tool: config.Tool{CLI: "python"},
+			path: system,
+		},
+		{
+			name: "pattern matches",
+			tool: config.Tool{CLI: "python", PathPattern: `/mise/installs/`},
+			path: managed,
+		},
+		{
+			name:        "pattern doesn't match",
+			tool:        config.Tool{CLI: "python", PathPattern: `^/opt/homebrew/|/mise/installs/`},
+			path:        system,
+			errContains: "does not match",
+		},
+		{
+			name: "pattern matches symlink target",
+			tool: config.Tool{CLI: "jq", PathPattern: `/Cellar/`},
+			path: link,
+		},
+		{
+			name:        "invalid pattern",
+			tool:        config.Tool{CLI: "python", PathPattern: `(`},
+			path:        system,
+			errContains: "invalid path pattern",
+		},
+		{
+			name: "managed by mise",
+			tool: config.Tool{CLI: "python", ManagedBy: "mise"},
+			path: managed,
+		},
+		{
+			name:        "not managed",
+			tool:        config.Tool{CLI: "python", ManagedBy: "mise"},
+			path:        system,
+			errContains: "managed by no known manager instead of mise",
+		},
+		{
+			name: "managed by homebrew through symlink",
+			tool: config.Tool{CLI: "jq", ManagedBy: "homebrew"},
+			path: link,
+		},
+		{
+			name:        "managed by another manager",
+			tool:        config.Tool{CLI: "python", ManagedBy: "asdf"},
+			path:        managed,
+			errContains: "managed by mise instead of asdf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateResolvedPath(&tt.tool, tt.path)
+
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+func TestCheckPathConstraints(t *testing.T) {
+	goPath, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found on PATH")
+	}
+
+	tmpDir := t.TempDir()
+	fake := writeFakeTool(t, filepath.Join(tmpDir, "usr", "bin"), "faketool", "faketool 1.2.3")
+	t.Setenv("PATH", filepath.Dir(fake)+string(os.PathListSeparator)+filepath.Dir(goPath))
+
+	t.Run("version check fails on unexpected location", func(t *testing.T) {
+		result := Check(&config.Tool{
+			Name:        "faketool",
+			CLI:         "faketool",
+			Version:     ">=1.0.0",
+			PathPattern: `^/opt/homebrew/`,
+		})
+
+		if result.Status != StatusFail {
+			t.Errorf("expected StatusFail, got %v", result.Status)
+		}
+		if result.Path != fake {
+			t.Errorf("expected path %q, got %q", fake, result.Path)
+		}
+	})
+
+	t.Run("existence check passes on expected location", func(t *testing.T) {
+		result := Check(&config.Tool{
+			Name:        "faketool",
+			CLI:         "faketool",
+			PathPattern: "^" + tmpDir,
+		})
+
+		if result.Status != StatusPass {
+			t.Errorf("expected StatusPass, got %v (error: %v)", result.Status, result.Error)
+		}
+	})
+
+	t.Run("alternatives skip candidates in unexpected locations", func(t *testing.T) {
+		result := Check(&config.Tool{
+			Name:         "runtime",
+			PathPattern:  "^" + tmpDir,
+			Alternatives: []config.Alternative{{CLI: "go"}, {CLI: "faketool"}},
+		})
+
+		if result.Status != StatusPass {
+			t.Fatalf("expected StatusPass, got %v (error: %v)", result.Status, result.Error)
+		}
+		if result.Selected.CLI != "faketool" {
+			t.Errorf("expected faketool to be selected, got %q", result.Selected.CLI)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/drape-io/chex/internal/config"
+)
+
+// schemePatterns are the default extraction patterns for schemes whose versions the
+// generic X.Y.Z pattern would truncate (1.2.3.4, 3.12.0rc1, 20240315).
+var schemePatterns = map[string]string{
+	config.SchemeCalver: `\b(?:\d{4}|\d{2})\.\d{1,2}(?:\.\d+)*(?:-[0-9A-Za-z.]+)?`,
+	config.SchemeLoose:  `\d+(?:\.\d+)+(?:-[0-9A-Za-z.]+)?`,
+	config.SchemePEP440: `(?i)(?:\d+!)?\d+(?:\.\d+)*` +
+		`(?:[-_.]?(?:a|b|c|rc|alpha|beta|pre|preview)[-_.]?\d*)?` +
+		`(?:[-_.]?(?:post|rev|r)[-_.]?\d*)?(?:[-_.]?dev[-_.]?\d*)?`,
+	config.SchemeDate: `\b(?:\d{4}-\d{2}-\d{2}|\d{8})\b`,
+}
+
+// reasonPrereleaseExcluded explains a failure caused only by the prerelease policy.
+const reasonPrereleaseExcluded = "prerelease excluded by policy"
+
+// versionSatisfies reports whether installed meets the tool's constraint under its
+// version scheme. When it doesn't, reason explains failures that aren't simply an
+// out-of-range version.
+func versionSatisfies(tool *config.Tool, installed string) (satisfied bool, reason string, err error) {
+	constraint := tool.Version
+
+	switch tool.VersionScheme {
+	case "", config.SchemeSemver:
+		allowPrerelease := tool.AllowPrerelease != nil && *tool.AllowPrerelease
+		return semverSatisfies(constraint, installed, allowPrerelease)
+	case config.SchemeCalver, config.SchemePEP440, config.SchemeLoose, config.SchemeDate:
+		c, err := config.ParseVersionConstraint(tool.VersionScheme, constraint)
+		if err != nil {
+			return false, "", fmt.Errorf("invalid version constraint '%s': %w", constraint, err)
+		}
+		satisfied, err := c.Check(installed)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to parse installed version '%s': %w", installed, err)
+		}
+		return satisfied, "", nil
+	default:
+		return false, "", fmt.Errorf(
+			"unknown version scheme %q (expected semver, calver, pep440, loose or date)", tool.VersionScheme,
+		)
+	}
+}
+
+// semverSatisfies checks a semver constraint. Prereleases only satisfy constraints
+// that mention a prerelease themselves, unless allowPrerelease is set.
+func semverSatisfies(constraint, installed string, allowPrerelease bool) (bool, string, error) {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false, "", fmt.Errorf("invalid version constraint '%s': %w", constraint, err)
+	}
+	v, err := semver.NewVersion(installed)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to parse installed version '%s': %w", installed, err)
+	}
+
+	c.IncludePrerelease = allowPrerelease
+	if c.Check(v) {
+		return true, "", nil
+	}
+
+	// Say so when the version is in range and only the policy rejected it
+	if v.Prerelease() != "" && !allowPrerelease {
+		c.IncludePrerelease = true
+		if c.Check(v) {
+			return false, reasonPrereleaseExcluded, nil
+		}
+	}
+	return false, "", nil
+}
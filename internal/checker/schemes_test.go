@@ -0,0 +1,437 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+func TestVersionSatisfies(t *testing.T) {
+	tests := []struct {
+		name        string
+		scheme      string
+		constraint  string
+		installed   string
+		expected    bool
+		errContains string
+	}{
+		{
+			name:       "semver by default",
+			constraint: "^1.20.0",
+			installed:  "1.21.3",
+			expected:   true,
+		},
+		{
+			name:       "semver mismatch",
+			scheme:     "semver",
+			constraint: ">=2.0.0",
+			installed:  "1.21.3",
+		},
+		{
+			name:        "semver bad version",
+			constraint:  ">=1.0.0",
+			installed:   "1.2.3.4",
+			errContains: "failed to parse",
+		},
+		{
+			name:       "calver newer",
+			scheme:     "calver",
+			constraint: ">=2024.01",
+			installed:  "2024.03.1",
+			expected:   true,
+		},
+		{
+			name:       "calver older",
+			scheme:     "calver",
+			constraint: ">=2024.04",
+			installed:  "2024.03.1",
+		},
+		{
+			name:       "calver wildcard",
+			scheme:     "calver",
+			constraint: "==2024.*",
+			installed:  "2024.03.1",
+			expected:   true,
+		},
+		{
+			name:       "calver leading zeros",
+			scheme:     "calver",
+			constraint: "==2024.3.1",
+			installed:  "2024.03.01",
+			expected:   true,
+		},
+		{
+			name:       "calver pre-release",
+			scheme:     "calver",
+			constraint: ">=2024.03.1",
+			installed:  "2024.03.1-beta",
+		},
+		{
+			name:       "loose four-part",
+			scheme:     "loose",
+			constraint: ">1.2.3.3",
+			installed:  "1.2.3.4",
+			expected:   true,
+		},
+		{
+			name:       "loose four-part order",
+			scheme:     "loose",
+			constraint: "<1.2.3.10",
+			installed:  "1.2.3.9",
+			expected:   true,
+		},
+		{
+			name:       "loose zero padding",
+			scheme:     "loose",
+			constraint: "=1.2",
+			installed:  "1.2.0.0",
+			expected:   true,
+		},
+		{
+			name:       "loose range",
+			scheme:     "loose",
+			constraint: ">=1.0, <2.0",
+			installed:  "2.0.0.1",
+		},
+		{
+			name:       "loose or",
+			scheme:     "loose",
+			constraint: "<1.0 || >=2.0",
+			installed:  "2.0.0.1",
+			expected:   true,
+		},
+		{
+			name:       "pep440 rc before final",
+			scheme:     "pep440",
+			constraint: ">=3.12.0",
+			installed:  "3.12.0rc1",
+		},
+		{
+			name:       "pep440 rc after beta",
+			scheme:     "pep440",
+			constraint: ">3.12.0b4",
+			installed:  "3.12.0rc1",
+			expected:   true,
+		},
+		{
+			name:       "pep440 dev before alpha",
+			scheme:     "pep440",
+			constraint: "<3.12.0a1",
+			installed:  "3.12.0.dev2",
+			expected:   true,
+		},
+		{
+			name:       "pep440 post after final",
+			scheme:     "pep440",
+			constraint: ">1.0",
+			installed:  "1.0.post1",
+			expected:   true,
+		},
+		{
+			name:       "pep440 compatible",
+			scheme:     "pep440",
+			constraint: "~=3.10",
+			installed:  "3.12.1",
+			expected:   true,
+		},
+		{
+			name:       "pep440 compatible major",
+			scheme:     "pep440",
+			constraint: "~=3.10",
+			installed:  "4.0.0",
+		},
+		{
+			name:       "pep440 exclusion",
+			scheme:     "pep440",
+			constraint: ">=3.8, !=3.9.*",
+			installed:  "3.9.7",
+		},
+		{
+			name:       "pep440 epoch",
+			scheme:     "pep440",
+			constraint: ">=2.0",
+			installed:  "1!1.0",
+			expected:   true,
+		},
+		{
+			name:       "date compact",
+			scheme:     "date",
+			constraint: ">=20240101",
+			installed:  "20240315",
+			expected:   true,
+		},
+		{
+			name:       "date mixed formats",
+			scheme:     "date",
+			constraint: "<2024-03-01",
+			installed:  "20240315",
+		},
+		{
+			name:        "date invalid",
+			scheme:      "date",
+			constraint:  ">=20240101",
+			installed:   "20241345",
+			errContains: "not a valid date",
+		},
+		{
+			name:        "unknown scheme",
+			scheme:      "roman",
+			constraint:  ">=IV",
+			installed:   "V",
+			errContains: "unknown version scheme",
+		},
+		{
+			name:        "bad wildcard",
+			scheme:      "loose",
+			constraint:  ">=1.*",
+			installed:   "1.0",
+			errContains: "invalid version constraint",
+		},
+		{
+			name:        "unknown operator",
+			scheme:      "loose",
+			constraint:  "~1.2",
+			installed:   "1.2.0",
+			errContains: `unknown operator "~"`,
+		},
+		{
+			name:        "calver needs a calendar version",
+			scheme:      "calver",
+			constraint:  ">=2024.01",
+			installed:   "1.2.3",
+			errContains: "not a calendar version",
+		},
+		{
+			name:        "bad compatible",
+			scheme:      "pep440",
+			constraint:  "~=3",
+			installed:   "3.1",
+			errContains: "two release segments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := &config.Tool{Version: tt.constraint, VersionScheme: tt.scheme}
+			satisfied, _, err := versionSatisfies(tool, tt.installed)
+
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if satisfied != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, satisfied)
+			}
+		})
+	}
+}
+
+func TestSchemeDefaultPatterns(t *testing.T) {
+	tests := []struct {
+		scheme   string
+		output   string
+		expected string
+	}{
+		{
+			scheme:   "pep440",
+			output:   "Python 3.12.0rc1",
+			expected: "3.12.0rc1",
+		},
+		{
+			scheme:   "loose",
+			output:   "Tool version 1.2.3.4 (build 77)",
+			expected: "1.2.3.4",
+		},
+		{
+			scheme:   "calver",
+			output:   "pip-tools 2024.03.1",
+			expected: "2024.03.1",
+		},
+		{
+			scheme:   "date",
+			output:   "release 20240315 (linux)",
+			expected: "20240315",
+		},
+		{
+			scheme:   "semver",
+			output:   "tool 1.2.3.4",
+			expected: "1.2.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scheme, func(t *testing.T) {
+			version, err := extractToolVersion(&config.Tool{VersionScheme: tt.scheme}, tt.output)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if version != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, version)
+			}
+		})
+	}
+}
+
+func TestPrereleasePolicy(t *testing.T) {
+	allow, deny := true, false
+
+	tests := []struct {
+		name           string
+		constraint     string
+		installed      string
+		allow          *bool
+		expected       bool
+		expectedReason string
+	}{
+		{
+			name:           "prerelease excluded by default",
+			constraint:     ">=20",
+			installed:      "23.0.0-nightly",
+			expectedReason: reasonPrereleaseExcluded,
+		},
+		{
+			name:           "prerelease excluded explicitly",
+			constraint:     ">=20",
+			installed:      "23.0.0-nightly",
+			allow:          &deny,
+			expectedReason: reasonPrereleaseExcluded,
+		},
+		{
+			name:       "prerelease allowed",
+			constraint: ">=20",
+			installed:  "23.0.0-nightly",
+			allow:      &allow,
+			expected:   true,
+		},
+		{
+			name:       "allowed prerelease still out of range",
+			constraint: ">=24",
+			installed:  "23.0.0-nightly",
+			allow:      &allow,
+		},
+		{
+			name:       "prerelease out of range has no policy reason",
+			constraint: ">=24",
+			installed:  "23.0.0-nightly",
+		},
+		{
+			name:       "constraint mentioning a prerelease",
+			constraint: ">=23.0.0-0",
+			installed:  "23.0.0-nightly",
+			expected:   true,
+		},
+		{
+			name:       "build metadata is ignored",
+			constraint: ">=2.1.0",
+			installed:  "2.1.0+homebrew",
+			expected:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := &config.Tool{Version: tt.constraint, AllowPrerelease: tt.allow}
+			satisfied, reason, err := versionSatisfies(tool, tt.installed)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if satisfied != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, satisfied)
+			}
+			if reason != tt.expectedReason {
+				t.Errorf("expected reason %q, got %q", tt.expectedReason, reason)
+			}
+		})
+	}
+}
+
+func TestDefaultPatternPrerelease(t *testing.T) {
+	allow := true
+
+	tests := []struct {
+		name       string
+		output     string
+		allow      *bool
+		constraint string
+		expected   string
+		status     Status
+		reason     string
+	}{
+		{
+			name:       "debian revision",
+			output:     "git version 2.34.1-1ubuntu1",
+			constraint: ">=2.30",
+			expected:   "2.34.1",
+			status:     StatusPass,
+		},
+		{
+			name:       "ubuntu package revision",
+			output:     "curl 7.81.0-1ubuntu1.15 (x86_64-pc-linux-gnu)",
+			constraint: ">=7.81.0",
+			expected:   "7.81.0",
+			status:     StatusPass,
+		},
+		{
+			name:       "revision before build metadata",
+			output:     "tool 1.2.3-1+deb12u1",
+			constraint: ">=1.2.0",
+			expected:   "1.2.3+deb12u1",
+			status:     StatusPass,
+		},
+		{
+			name:       "prerelease excluded by default",
+			output:     "tool 1.2.3-beta",
+			constraint: ">=1.2.0",
+			expected:   "1.2.3-beta",
+			status:     StatusFail,
+			reason:     reasonPrereleaseExcluded,
+		},
+		{
+			name:       "nightly excluded by default",
+			output:     "node v23.0.0-nightly",
+			constraint: ">=20",
+			expected:   "23.0.0-nightly",
+			status:     StatusFail,
+			reason:     reasonPrereleaseExcluded,
+		},
+		{
+			name:       "numeric prerelease",
+			output:     "tool 1.0.0-0.3.7",
+			constraint: ">=0.9.0",
+			expected:   "1.0.0-0.3.7",
+			status:     StatusFail,
+			reason:     reasonPrereleaseExcluded,
+		},
+		{
+			name:       "prerelease kept when allowed",
+			output:     "node v23.0.0-nightly",
+			allow:      &allow,
+			constraint: ">=20",
+			expected:   "23.0.0-nightly",
+			status:     StatusPass,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := &config.Tool{Version: tt.constraint, AllowPrerelease: tt.allow}
+			result := evaluateVersion(tool, &Result{Tool: tool}, tt.output)
+
+			if result.InstalledVersion != tt.expected {
+				t.Errorf("expected version %q, got %q", tt.expected, result.InstalledVersion)
+			}
+			if result.Status != tt.status {
+				t.Errorf("expected %v, got %v (error: %v)", tt.status, result.Status, result.Error)
+			}
+			if result.Reason != tt.reason {
+				t.Errorf("expected reason %q, got %q", tt.reason, result.Reason)
+			}
+		})
+	}
+}
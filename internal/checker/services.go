@@ -0,0 +1,116 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// defaultDialTimeout bounds how long connecting to a service may take.
+const defaultDialTimeout = 2 * time.Second
+
+// CheckService checks that a local service is reachable and returns the result.
+func CheckService(service *config.ServiceRequirement) *Result {
+	network, address := "tcp", service.Address
+	if service.Socket != "" {
+		network, address = "unix", service.Socket
+	}
+
+	result := &Result{
+		Category: CategoryService,
+		Tool:     requirementTool(service.Name, address, service.Optional, service.Message),
+	}
+
+	err := probeService(service, network, address, result)
+	if err != nil {
+		result.Status = StatusFail
+		if service.Optional {
+			result.Status = StatusOptionalMissing
+		}
+		result.Error = err
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Path = address
+	return result
+}
+
+// probeService connects to the service and, if configured, performs an HTTP handshake.
+func probeService(service *config.ServiceRequirement, network, address string, result *Result) error {
+	if address == "" {
+		return errors.New("either address or socket is required")
+	}
+
+	timeout := defaultDialTimeout
+	if service.Timeout != "" {
+		parsed, err := time.ParseDuration(service.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout '%s': %w", service.Timeout, err)
+		}
+		timeout = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	if service.HTTPPath == "" {
+		conn, err := dialer.DialContext(ctx, network, address)
+		if err != nil {
+			return fmt.Errorf("%s is not reachable: %w", address, err)
+		}
+		return conn.Close()
+	}
+
+	// Route the request over the configured network so Unix sockets work too
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, address)
+		},
+	}
+	defer transport.CloseIdleConnections()
+	client := &http.Client{Transport: transport}
+
+	host := address
+	if network == "unix" {
+		host = "localhost"
+	}
+	path := service.HTTPPath
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+host+path, nil)
+	if err != nil {
+		return fmt.Errorf("invalid http_path '%s': %w", service.HTTPPath, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s is not reachable: %w", address, err)
+	}
+	_ = resp.Body.Close()
+
+	result.Output = resp.Status
+
+	expected := service.HTTPStatus
+	if expected == 0 {
+		expected = http.StatusOK
+	}
+	if resp.StatusCode != expected {
+		return fmt.Errorf("GET %s returned %d, expected %d", path, resp.StatusCode, expected)
+	}
+
+	return nil
+}
+
+// CheckServiceAll checks multiple service requirements, ordered by name.
+func CheckServiceAll(services map[string]*config.ServiceRequirement) []*Result {
+	return checkSorted(services, CheckService)
+}
@@ -0,0 +1,157 @@
+package checker
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+func TestCheckService(t *testing.T) {
+	t.Run("reachable TCP port", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = listener.Close() }()
+
+		result := CheckService(&config.ServiceRequirement{
+			Name:    "postgres",
+			Address: listener.Addr().String(),
+		})
+
+		if result.Status != StatusPass {
+			t.Errorf("expected StatusPass, got %v (error: %v)", result.Status, result.Error)
+		}
+		if result.Category != CategoryService {
+			t.Errorf("expected CategoryService, got %v", result.Category)
+		}
+	})
+
+	t.Run("unreachable TCP port", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		address := listener.Addr().String()
+		_ = listener.Close()
+
+		result := CheckService(&config.ServiceRequirement{Name: "redis", Address: address})
+
+		if result.Status != StatusFail {
+			t.Errorf("expected StatusFail, got %v", result.Status)
+		}
+		if result.Error == nil || !strings.Contains(result.Error.Error(), "not reachable") {
+			t.Errorf("expected not reachable error, got %v", result.Error)
+		}
+	})
+
+	t.Run("optional unreachable service", func(t *testing.T) {
+		result := CheckService(&config.ServiceRequirement{
+			Name:     "docker",
+			Socket:   filepath.Join(t.TempDir(), "missing.sock"),
+			Optional: true,
+		})
+
+		if result.Status != StatusOptionalMissing {
+			t.Errorf("expected StatusOptionalMissing, got %v", result.Status)
+		}
+	})
+
+	t.Run("reachable Unix socket", func(t *testing.T) {
+		socket := filepath.Join(t.TempDir(), "docker.sock")
+		listener, err := net.Listen("unix", socket)
+		if err != nil {
+			t.Skipf("unix sockets not supported: %v", err)
+		}
+		defer func() { _ = listener.Close() }()
+
+		result := CheckService(&config.ServiceRequirement{Name: "docker", Socket: socket})
+
+		if result.Status != StatusPass {
+			t.Errorf("expected StatusPass, got %v (error: %v)", result.Status, result.Error)
+		}
+		if result.Path != socket {
+			t.Errorf("expected path %q, got %q", socket, result.Path)
+		}
+	})
+
+	t.Run("HTTP handshake over Unix socket", func(t *testing.T) {
+		socket := filepath.Join(t.TempDir(), "api.sock")
+		listener, err := net.Listen("unix", socket)
+		if err != nil {
+			t.Skipf("unix sockets not supported: %v", err)
+		}
+		server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})}
+		go func() { _ = server.Serve(listener) }()
+		defer func() { _ = server.Close() }()
+
+		result := CheckService(&config.ServiceRequirement{Name: "docker", Socket: socket, HTTPPath: "/_ping"})
+
+		if result.Status != StatusPass {
+			t.Errorf("expected StatusPass, got %v (error: %v)", result.Status, result.Error)
+		}
+	})
+
+	t.Run("missing address", func(t *testing.T) {
+		result := CheckService(&config.ServiceRequirement{Name: "nothing"})
+
+		if result.Status != StatusFail {
+			t.Errorf("expected StatusFail, got %v", result.Status)
+		}
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		result := CheckService(&config.ServiceRequirement{Name: "db", Address: "127.0.0.1:1", Timeout: "soon"})
+
+		if result.Error == nil || !strings.Contains(result.Error.Error(), "invalid timeout") {
+			t.Errorf("expected invalid timeout error, got %v", result.Error)
+		}
+	})
+}
+
+func TestCheckServiceHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	tests := []struct {
+		name     string
+		path     string
+		status   int
+		expected Status
+	}{
+		{name: "default status", path: "/healthz", expected: StatusPass},
+		{name: "path without leading slash", path: "healthz", expected: StatusPass},
+		{name: "expected status", path: "/ready", status: http.StatusServiceUnavailable, expected: StatusPass},
+		{name: "unexpected status", path: "/ready", expected: StatusFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CheckService(&config.ServiceRequirement{
+				Name:       "api",
+				Address:    address,
+				HTTPPath:   tt.path,
+				HTTPStatus: tt.status,
+			})
+
+			if result.Status != tt.expected {
+				t.Errorf("expected %v, got %v (error: %v)", tt.expected, result.Status, result.Error)
+			}
+		})
+	}
+}
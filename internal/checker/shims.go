@@ -0,0 +1,180 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/drape-io/chex/internal/config"
+)
+
+// ErrNotInstalledByManager indicates that a shim exists for a tool but its version
+// manager has no installation to run for it.
+var ErrNotInstalledByManager = errors.New("not installed by version manager")
+
+// resolveShim replaces a mise/asdf shim with the binary it would run, recording the
+// shim on the result. Paths that aren't shims are returned unchanged.
+func resolveShim(tool *config.Tool, result *Result, path string) (string, error) {
+	manager := shimManager(path)
+	if manager == "" {
+		return path, nil
+	}
+
+	result.ShimPath = path
+
+	target, err := whichManaged(manager, filepath.Base(path), tool)
+	if err != nil {
+		result.Reason = ErrNotInstalledByManager.Error()
+		return "", fmt.Errorf("%s: %w (%s): %w", tool.CLI, ErrNotInstalledByManager, manager, err)
+	}
+
+	return target, nil
+}
+
+// whichManaged asks the version manager which binary its shim would run for tool,
+// falling back to searching the manager's install directory when the manager isn't
+// on PATH. The manager runs in the tool's working directory and environment, where
+// it reads the same version files the shim would.
+func whichManaged(manager, cli string, tool *config.Tool) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, manager, "which", cli)
+	applyToolEnv(cmd, tool)
+	output, err := cmd.Output()
+	if err == nil {
+		if target := strings.TrimSpace(firstLine(string(output))); target != "" {
+			return target, nil
+		}
+		return "", fmt.Errorf("%s which %s returned no path", manager, cli)
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if msg := strings.TrimSpace(string(exitErr.Stderr)); msg != "" {
+			return "", errors.New(firstLine(msg))
+		}
+		return "", err
+	}
+
+	return findInstalledBinary(manager, cli, tool)
+}
+
+// findInstalledBinary finds the binary the shim would run when the manager itself
+// isn't on PATH: the installation of the version the manager selects for cli's
+// plugin. It doesn't guess when no version is selected.
+func findInstalledBinary(manager, cli string, tool *config.Tool) (string, error) {
+	pattern := filepath.Join(managerDataDir(manager), "installs", "*", "*", "bin", cli)
+	matches, _ := filepath.Glob(pattern)
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no installation of %s found", cli)
+	}
+
+	var plugins []string
+	for _, match := range matches {
+		plugins = append(plugins, installedPlugin(match))
+	}
+	slices.Sort(plugins)
+
+	for _, plugin := range slices.Compact(plugins) {
+		version, source := selectedVersion(manager, plugin, tool)
+		if version == "" {
+			continue
+		}
+		if binary := selectedInstallation(manager, plugin, version, matches); binary != "" {
+			return binary, nil
+		}
+		return "", fmt.Errorf("%s %s is selected by %s but not installed", plugin, version, source)
+	}
+
+	return "", fmt.Errorf("no version of %s is selected; cannot tell which installation the shim runs", cli)
+}
+
+// selectedVersion returns the version the manager selects for plugin, and where it
+// was selected: the manager's version environment variable, then the nearest version
+// file from the tool's working directory up, then the manager's global config.
+func selectedVersion(manager, plugin string, tool *config.Tool) (version, source string) {
+	name := strings.ToUpper(manager + "_" + strings.ReplaceAll(plugin, "-", "_") + "_VERSION")
+	if version := toolEnv(tool, name); version != "" {
+		return version, name
+	}
+
+	dir := tool.Workdir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	for {
+		for _, file := range versionFiles(manager) {
+			path := filepath.Join(dir, file)
+			if version := config.ReadVersionFile(path, plugin); version != "" {
+				return version, path
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	global := globalVersionFile(manager)
+	if version := config.ReadVersionFile(global, plugin); version != "" {
+		return version, global
+	}
+	return "", ""
+}
+
+// selectedInstallation returns the installed binary for the selected version. mise
+// also accepts a prefix such as "18" and runs the newest matching installation.
+func selectedInstallation(manager, plugin, version string, matches []string) string {
+	var candidates []string
+	for _, match := range matches {
+		if installedPlugin(match) != plugin {
+			continue
+		}
+		installed := installedVersion(match)
+		if installed == version {
+			return match
+		}
+		if manager == config.ManagerMise && strings.HasPrefix(installed, version+".") {
+			candidates = append(candidates, match)
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b string) int {
+		return compareInstalledVersions(installedVersion(b), installedVersion(a))
+	})
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[0]
+}
+
+// installedPlugin returns the plugin a managed binary is installed under, e.g.
+// nodejs for .../installs/nodejs/18.19.0/bin/node.
+func installedPlugin(binary string) string {
+	return filepath.Base(filepath.Dir(filepath.Dir(filepath.Dir(binary))))
+}
+
+// installedVersion returns the version directory a managed binary is installed in,
+// e.g. 18.19.0 for .../installs/nodejs/18.19.0/bin/node.
+func installedVersion(binary string) string {
+	return filepath.Base(filepath.Dir(filepath.Dir(binary)))
+}
+
+// compareInstalledVersions orders version directories, falling back to comparing
+// names for versions that aren't semver.
+func compareInstalledVersions(a, b string) int {
+	va, errA := semver.NewVersion(a)
+	vb, errB := semver.NewVersion(b)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	return va.Compare(vb)
+}
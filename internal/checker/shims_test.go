@@ -0,0 +1,198 @@
+package checker
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// setupMiseShim creates a mise data dir with a shim for faketool that fails if it is
+// executed directly, and an installed faketool 2.0.0 behind it.
+func setupMiseShim(t *testing.T) (shim, target string) {
+	t.Helper()
+	dataDir := filepath.Join(t.TempDir(), "mise")
+	t.Setenv("MISE_DATA_DIR", dataDir)
+	t.Setenv("MISE_CONFIG_DIR", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	shim = writeFakeTool(t, filepath.Join(dataDir, "shims"), "faketool", "mise ERROR shim executed")
+	target = writeFakeTool(
+		t, filepath.Join(dataDir, "installs", "faketool", "2.0.0", "bin"), "faketool", "faketool 2.0.0",
+	)
+	return shim, target
+}
+
+func TestResolveShim(t *testing.T) {
+	t.Run("resolves through mise which", func(t *testing.T) {
+		shim, target := setupMiseShim(t)
+		binDir := filepath.Join(t.TempDir(), "bin")
+		writeFakeTool(t, binDir, "mise", target)
+		t.Setenv("PATH", filepath.Dir(shim)+string(os.PathListSeparator)+binDir)
+
+		result := Check(&config.Tool{Name: "faketool", CLI: "faketool", Version: ">=2.0.0"})
+
+		if result.Status != StatusPass {
+			t.Fatalf("expected StatusPass, got %v (error: %v)", result.Status, result.Error)
+		}
+		if result.ShimPath != shim {
+			t.Errorf("expected shim %q, got %q", shim, result.ShimPath)
+		}
+		if result.Path != target {
+			t.Errorf("expected target %q, got %q", target, result.Path)
+		}
+		if result.InstalledVersion != "2.0.0" {
+			t.Errorf("expected version from target, got %q", result.InstalledVersion)
+		}
+	})
+
+	t.Run("reports tools the manager hasn't installed", func(t *testing.T) {
+		shim, _ := setupMiseShim(t)
+		binDir := filepath.Join(t.TempDir(), "bin")
+		if err := os.MkdirAll(binDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		script := "#!/bin/sh\necho 'mise ERROR No version is set for shim: faketool' >&2\nexit 1\n"
+		if err := os.WriteFile(filepath.Join(binDir, "mise"), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("PATH", filepath.Dir(shim)+string(os.PathListSeparator)+binDir)
+
+		result := Check(&config.Tool{Name: "faketool", CLI: "faketool"})
+
+		if result.Status != StatusFail {
+			t.Errorf("expected StatusFail, got %v", result.Status)
+		}
+		if !errors.Is(result.Error, ErrNotInstalledByManager) {
+			t.Errorf("expected ErrNotInstalledByManager, got %v", result.Error)
+		}
+		if result.Reason != ErrNotInstalledByManager.Error() {
+			t.Errorf("expected reason to be set, got %q", result.Reason)
+		}
+		if result.ShimPath != shim {
+			t.Errorf("expected shim %q, got %q", shim, result.ShimPath)
+		}
+	})
+
+	t.Run("falls back to install directories without mise on PATH", func(t *testing.T) {
+		shim, target := setupMiseShim(t)
+		t.Setenv("PATH", filepath.Dir(shim))
+		project := t.TempDir()
+		writeTestFile(t, filepath.Join(project, "mise.toml"), "[tools]\nfaketool = \"2.0.0\"\n")
+
+		result := Check(&config.Tool{Name: "faketool", CLI: "faketool", Workdir: project})
+
+		if result.Status != StatusPass {
+			t.Fatalf("expected StatusPass, got %v (error: %v)", result.Status, result.Error)
+		}
+		if result.Path != target {
+			t.Errorf("expected target %q, got %q", target, result.Path)
+		}
+	})
+
+	t.Run("fails without mise on PATH when no version is selected", func(t *testing.T) {
+		shim, _ := setupMiseShim(t)
+		t.Setenv("PATH", filepath.Dir(shim))
+
+		result := Check(&config.Tool{Name: "faketool", CLI: "faketool", Workdir: t.TempDir()})
+
+		if !errors.Is(result.Error, ErrNotInstalledByManager) {
+			t.Errorf("expected ErrNotInstalledByManager, got %v", result.Error)
+		}
+	})
+
+	t.Run("leaves regular binaries alone", func(t *testing.T) {
+		path := writeFakeTool(t, filepath.Join(t.TempDir(), "bin"), "faketool", "faketool 1.0.0")
+
+		resolved, err := resolveShim(&config.Tool{CLI: "faketool"}, &Result{}, path)
+
+		if err != nil || resolved != path {
+			t.Errorf("expected %q unchanged, got %q (error: %v)", path, resolved, err)
+		}
+	})
+}
+
+func TestFindInstalledBinary(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("ASDF_NODEJS_VERSION", "")
+	dataDir := filepath.Join(t.TempDir(), "asdf")
+	t.Setenv("ASDF_DATA_DIR", dataDir)
+
+	v18 := writeFakeTool(t, filepath.Join(dataDir, "installs", "nodejs", "18.19.0", "bin"), "node", "v18.19.0")
+	v20 := writeFakeTool(t, filepath.Join(dataDir, "installs", "nodejs", "20.11.0", "bin"), "node", "v20.11.0")
+
+	project := t.TempDir()
+	writeTestFile(t, filepath.Join(project, ".tool-versions"), "nodejs 18.19.0\n")
+
+	t.Run("uses the version the project selects", func(t *testing.T) {
+		// v20 also satisfies the constraint, but the shim runs v18
+		path, err := findInstalledBinary("asdf", "node", &config.Tool{Version: ">=18", Workdir: project})
+		if err != nil || path != v18 {
+			t.Errorf("expected %q, got %q (error: %v)", v18, path, err)
+		}
+	})
+
+	t.Run("searches parent directories", func(t *testing.T) {
+		nested := filepath.Join(project, "src", "app")
+		if err := os.MkdirAll(nested, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		path, err := findInstalledBinary("asdf", "node", &config.Tool{Workdir: nested})
+		if err != nil || path != v18 {
+			t.Errorf("expected %q, got %q (error: %v)", v18, path, err)
+		}
+	})
+
+	t.Run("environment variable overrides version files", func(t *testing.T) {
+		tool := &config.Tool{Workdir: project, Env: map[string]string{"ASDF_NODEJS_VERSION": "20.11.0"}}
+		path, err := findInstalledBinary("asdf", "node", tool)
+		if err != nil || path != v20 {
+			t.Errorf("expected %q, got %q (error: %v)", v20, path, err)
+		}
+	})
+
+	t.Run("falls back to the global version", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		writeTestFile(t, filepath.Join(home, ".tool-versions"), "nodejs 20.11.0\n")
+
+		path, err := findInstalledBinary("asdf", "node", &config.Tool{Workdir: t.TempDir()})
+		if err != nil || path != v20 {
+			t.Errorf("expected %q, got %q (error: %v)", v20, path, err)
+		}
+	})
+
+	t.Run("selected version not installed", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestFile(t, filepath.Join(dir, ".tool-versions"), "nodejs 22.0.0\n")
+
+		_, err := findInstalledBinary("asdf", "node", &config.Tool{Workdir: dir})
+		if err == nil || !strings.Contains(err.Error(), "22.0.0 is selected by") {
+			t.Errorf("expected an error for the uninstalled version, got %v", err)
+		}
+	})
+
+	t.Run("no version selected", func(t *testing.T) {
+		if _, err := findInstalledBinary("asdf", "node", &config.Tool{Workdir: t.TempDir()}); err == nil {
+			t.Error("expected error when no version is selected")
+		}
+	})
+
+	t.Run("mise runs the newest installation matching a prefix", func(t *testing.T) {
+		miseDir := filepath.Join(t.TempDir(), "mise")
+		t.Setenv("MISE_DATA_DIR", miseDir)
+		writeFakeTool(t, filepath.Join(miseDir, "installs", "node", "18.19.0", "bin"), "node", "v18.19.0")
+		v1820 := writeFakeTool(t, filepath.Join(miseDir, "installs", "node", "18.20.0", "bin"), "node", "v18.20.0")
+		writeFakeTool(t, filepath.Join(miseDir, "installs", "node", "20.11.0", "bin"), "node", "v20.11.0")
+		dir := t.TempDir()
+		writeTestFile(t, filepath.Join(dir, "mise.toml"), "[tools]\nnode = \"18\"\n")
+
+		path, err := findInstalledBinary("mise", "node", &config.Tool{Workdir: dir})
+		if err != nil || path != v1820 {
+			t.Errorf("expected %q, got %q (error: %v)", v1820, path, err)
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/drape-io/chex/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// pathSegment is one step of a version path: a map key or, when isIndex is set, an array index.
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// extractToolVersion extracts the version from a tool's output, first selecting the
+// value at version_json_path or version_yaml_path when the output is structured.
+// The captured version then goes through the tool's normalization pipeline.
+func extractToolVersion(tool *config.Tool, output string) (string, error) {
+	if tool.VersionJSON != "" && tool.VersionYAML != "" {
+		return "", errors.New("set only one of version_json_path and version_yaml_path")
+	}
+
+	switch {
+	case tool.VersionJSON != "":
+		value, err := lookupStructuredValue(output, tool.VersionJSON, decodeJSONOutput)
+		if err != nil {
+			return "", fmt.Errorf("version_json_path %s: %w", tool.VersionJSON, err)
+		}
+		output = value
+	case tool.VersionYAML != "":
+		value, err := lookupStructuredValue(output, tool.VersionYAML, decodeYAMLOutput)
+		if err != nil {
+			return "", fmt.Errorf("version_yaml_path %s: %w", tool.VersionYAML, err)
+		}
+		output = value
+	}
+
+	pattern, err := capturePattern(tool)
+	if err != nil {
+		return "", err
+	}
+	version, err := extractVersion(output, pattern)
+	if err != nil {
+		return "", err
+	}
+	return normalizeVersion(tool, version)
+}
+
+// lookupStructuredValue decodes output and returns the scalar at path as a string.
+func lookupStructuredValue(output, path string, decode func(string) (any, error)) (string, error) {
+	segments, err := parseValuePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := decode(output)
+	if err != nil {
+		return "", err
+	}
+
+	walked := ""
+	for _, segment := range segments {
+		if segment.isIndex {
+			walked += fmt.Sprintf("[%d]", segment.index)
+			items, ok := value.([]any)
+			if !ok {
+				return "", fmt.Errorf("%s is not an array", displayPath(walked))
+			}
+			if segment.index >= len(items) {
+				return "", fmt.Errorf("%s is out of range (length %d)", displayPath(walked), len(items))
+			}
+			value = items[segment.index]
+			continue
+		}
+
+		walked += "." + segment.key
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("%s is not an object", displayPath(strings.TrimSuffix(walked, "."+segment.key)))
+		}
+		if value, ok = fields[segment.key]; !ok {
+			return "", fmt.Errorf("%s not found in output", walked)
+		}
+	}
+
+	switch value := value.(type) {
+	case nil:
+		return "", errors.New("value is null")
+	case map[string]any, []any:
+		return "", errors.New("value is an object or array, not a version")
+	case string:
+		return value, nil
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
+
+// displayPath shows the root of a path as "." rather than an empty string.
+func displayPath(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
+
+// parseValuePath parses a jq-style path such as ".clientVersion.gitVersion" or ".items[0].version".
+func parseValuePath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	rest := strings.TrimSpace(path)
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: missing ]", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid path %q: bad index %q", path, rest[1:end])
+			}
+			segments = append(segments, pathSegment{index: index, isIndex: true})
+			rest = rest[end+1:]
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			segments = append(segments, pathSegment{key: rest[:end]})
+			rest = rest[end:]
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid path %q: no keys", path)
+	}
+	return segments, nil
+}
+
+// decodeJSONOutput decodes the first JSON value in output, skipping any text
+// (such as warnings) printed before it.
+func decodeJSONOutput(output string) (any, error) {
+	start := strings.IndexAny(output, "{[")
+	if start < 0 {
+		return nil, errors.New("no JSON found in output")
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(output[start:]))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON output: %w", err)
+	}
+	return value, nil
+}
+
+// decodeYAMLOutput decodes the first YAML document in output.
+func decodeYAMLOutput(output string) (any, error) {
+	var document yaml.Node
+	if err := yaml.NewDecoder(bytes.NewBufferString(output)).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML output: %w", err)
+	}
+	return yamlNodeValue(&document), nil
+}
+
+// yamlNodeValue converts a YAML node to maps, slices and strings. Scalars keep their
+// original text so versions like 1.20 aren't read as the number 1.2.
+func yamlNodeValue(node *yaml.Node) any {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return yamlNodeValue(node.Content[0])
+	case yaml.MappingNode:
+		fields := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			fields[node.Content[i].Value] = yamlNodeValue(node.Content[i+1])
+		}
+		return fields
+	case yaml.SequenceNode:
+		items := make([]any, 0, len(node.Content))
+		for _, item := range node.Content {
+			items = append(items, yamlNodeValue(item))
+		}
+		return items
+	case yaml.AliasNode:
+		return yamlNodeValue(node.Alias)
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return nil
+		}
+		return node.Value
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,140 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+func TestExtractToolVersion(t *testing.T) {
+	kubectlJSON := `{
+  "clientVersion": {"major": "1", "minor": "29", "gitVersion": "v1.29.2"},
+  "kustomizeVersion": "v5.0.4-0.20230601165947-6ce0bf390ce3"
+}`
+	helmYAML := "version: v3.14.0\ngoVersion: go1.21.5\nreleases:\n  - name: first\n    version: 1.20\n"
+
+	tests := []struct {
+		name           string
+		tool           config.Tool
+		output         string
+		expectedResult string
+		errContains    string
+	}{
+		{
+			name:           "json path",
+			tool:           config.Tool{VersionJSON: ".clientVersion.gitVersion"},
+			output:         kubectlJSON,
+			expectedResult: "1.29.2",
+		},
+		{
+			name:           "json path without leading dot",
+			tool:           config.Tool{VersionJSON: "terraform_version"},
+			output:         `{"terraform_version": "1.7.3", "platform": "linux_amd64"}`,
+			expectedResult: "1.7.3",
+		},
+		{
+			name:           "json after warning text",
+			tool:           config.Tool{VersionJSON: ".Client.Version"},
+			output:         "WARNING: context not set\n{\"Client\": {\"Version\": \"25.0.3\"}}",
+			expectedResult: "25.0.3",
+		},
+		{
+			name:           "json array index",
+			tool:           config.Tool{VersionJSON: ".[1].version"},
+			output:         `[{"version": "0.1.0"}, {"version": "2.3.4"}]`,
+			expectedResult: "2.3.4",
+		},
+		{
+			name:           "json path with pattern",
+			tool:           config.Tool{VersionJSON: ".clientVersion.gitVersion", VersionPattern: `v(\d+\.\d+)`},
+			output:         kubectlJSON,
+			expectedResult: "1.29",
+		},
+		{
+			name:        "json key missing",
+			tool:        config.Tool{VersionJSON: ".serverVersion.gitVersion"},
+			output:      kubectlJSON,
+			errContains: ".serverVersion not found in output",
+		},
+		{
+			name:        "json value is an object",
+			tool:        config.Tool{VersionJSON: ".clientVersion"},
+			output:      kubectlJSON,
+			errContains: "not a version",
+		},
+		{
+			name:        "json key on a string",
+			tool:        config.Tool{VersionJSON: ".kustomizeVersion.major"},
+			output:      kubectlJSON,
+			errContains: ".kustomizeVersion is not an object",
+		},
+		{
+			name:        "json index out of range",
+			tool:        config.Tool{VersionJSON: ".[5]"},
+			output:      `["1.0.0"]`,
+			errContains: "out of range",
+		},
+		{
+			name:        "output isn't json",
+			tool:        config.Tool{VersionJSON: ".version"},
+			output:      "tool version 1.2.3",
+			errContains: "no JSON found",
+		},
+		{
+			name:        "invalid path",
+			tool:        config.Tool{VersionJSON: ".items[x]"},
+			output:      `{"items": []}`,
+			errContains: "bad index",
+		},
+		{
+			name:           "yaml path",
+			tool:           config.Tool{VersionYAML: ".version"},
+			output:         helmYAML,
+			expectedResult: "3.14.0",
+		},
+		{
+			name:           "yaml keeps scalar text",
+			tool:           config.Tool{VersionYAML: ".releases[0].version"},
+			output:         helmYAML,
+			expectedResult: "1.20",
+		},
+		{
+			name:        "yaml key missing",
+			tool:        config.Tool{VersionYAML: ".clientVersion"},
+			output:      helmYAML,
+			errContains: "version_yaml_path .clientVersion: .clientVersion not found",
+		},
+		{
+			name:        "both paths set",
+			tool:        config.Tool{VersionJSON: ".version", VersionYAML: ".version"},
+			output:      helmYAML,
+			errContains: "set only one",
+		},
+		{
+			name:           "no path falls back to pattern extraction",
+			tool:           config.Tool{},
+			output:         "helm v3.14.0",
+			expectedResult: "3.14.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := extractToolVersion(&tt.tool, tt.output)
+
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expectedResult {
+				t.Errorf("expected %q, got %q", tt.expectedResult, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+// checkSubcommands probes each of the tool's subcommands and plugins and fails the
+// result if a required one is missing or doesn't match its version constraint. They
+// run on the binary the tool's check resolved, so a shim or alternative is probed
+// through the same binary whose version was checked.
+func checkSubcommands(tool *config.Tool, result *Result) {
+	checked := tool
+	if result.Selected != nil {
+		checked = result.Selected
+	}
+	cli := checked.CLI
+	if result.Path != "" {
+		cli = result.Path
+	}
+
+	for _, sub := range tool.Subcommands {
+		subResult := checkSubcommand(cli, checked, sub)
+		result.Subcommands = append(result.Subcommands, subResult)
+
+		if subResult.Status == StatusFail {
+			result.Status = StatusFail
+			if tool.Optional {
+				result.Status = StatusOptionalMissing
+			}
+		}
+	}
+}
+
+// checkSubcommand probes a single subcommand, e.g. `docker compose version`, and
+// checks its version using the same extraction logic as checkVersion. Its status
+// only reflects whether the subcommand itself is optional; checkSubcommands decides
+// what a failure means for the tool. The subcommand runs like the tool does and
+// compares versions by the tool's scheme and prerelease policy.
+func checkSubcommand(cli string, tool *config.Tool, sub config.Subcommand) *Result {
+	subTool := &config.Tool{
+		Name:            sub.Name,
+		CLI:             cli,
+		Version:         sub.Version,
+		VersionArg:      sub.VersionArg,
+		VersionPattern:  sub.VersionPattern,
+		VersionScheme:   tool.VersionScheme,
+		AllowPrerelease: tool.AllowPrerelease,
+		Optional:        sub.Optional,
+		Source:          tool.Source,
+		Shell:           tool.Shell,
+		Env:             tool.Env,
+		ClearEnv:        tool.ClearEnv,
+		Workdir:         tool.Workdir,
+	}
+	result := &Result{Tool: subTool}
+
+	output, err := executeVersionCommand(subTool, strings.Fields(sub.Name)...)
+	if err != nil {
+		result.Status = StatusFail
+		if subTool.Optional {
+			result.Status = StatusOptionalMissing
+		}
+		result.Error = fmt.Errorf("subcommand '%s %s' is not available: %w", cli, sub.Name, err)
+		return result
+	}
+
+	result.Output = output
+
+	if sub.Version == "" {
+		result.Status = StatusPass
+		return result
+	}
+
+	result = evaluateVersion(subTool, result, output)
+	// An optional subcommand never fails, even when its version can't be read
+	if result.Status == StatusFail && subTool.Optional {
+		result.Status = StatusOptionalMissing
+	}
+	return result
+}
@@ -0,0 +1,220 @@
+package checker
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/drape-io/chex/internal/config"
+)
+
+func TestCheckSubcommand(t *testing.T) {
+	tool := &config.Tool{Name: "go", CLI: "go"}
+
+	tests := []struct {
+		name     string
+		sub      config.Subcommand
+		expected Status
+	}{
+		{
+			name:     "subcommand with matching version",
+			sub:      config.Subcommand{Name: "env", VersionArg: "GOVERSION", Version: ">=1.0.0"},
+			expected: StatusPass,
+		},
+		{
+			name:     "subcommand with mismatched version",
+			sub:      config.Subcommand{Name: "env", VersionArg: "GOVERSION", Version: ">=999.0.0"},
+			expected: StatusFail,
+		},
+		{
+			name:     "subcommand without version",
+			sub:      config.Subcommand{Name: "env", VersionArg: "GOVERSION"},
+			expected: StatusPass,
+		},
+		{
+			name:     "missing subcommand",
+			sub:      config.Subcommand{Name: "nonexistent-subcommand", VersionArg: "version"},
+			expected: StatusFail,
+		},
+		{
+			name:     "missing optional subcommand",
+			sub:      config.Subcommand{Name: "nonexistent-subcommand", VersionArg: "version", Optional: true},
+			expected: StatusOptionalMissing,
+		},
+		{
+			name:     "optional subcommand with mismatched version",
+			sub:      config.Subcommand{Name: "env", VersionArg: "GOVERSION", Version: ">=999.0.0", Optional: true},
+			expected: StatusOptionalMissing,
+		},
+		{
+			name: "optional subcommand whose version can't be extracted",
+			sub: config.Subcommand{
+				Name: "env", VersionArg: "GOVERSION", VersionPattern: `nomatch(\d+)`, Version: ">=1.0.0", Optional: true,
+			},
+			expected: StatusOptionalMissing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checkSubcommand(tool.CLI, tool, tt.sub)
+
+			if result.Status != tt.expected {
+				t.Errorf("expected %v, got %v (error: %v)", tt.expected, result.Status, result.Error)
+			}
+			if result.Tool.Name != tt.sub.Name {
+				t.Errorf("expected result name %q, got %q", tt.sub.Name, result.Tool.Name)
+			}
+		})
+	}
+}
+
+func TestCheckWithSubcommands(t *testing.T) {
+	t.Run("fails tool when a required subcommand fails", func(t *testing.T) {
+		tool := &config.Tool{
+			Name: "go",
+			CLI:  "go",
+			Subcommands: []config.Subcommand{
+				{Name: "env", VersionArg: "GOVERSION", Version: ">=1.0.0"},
+				{Name: "nonexistent-subcommand", VersionArg: "version"},
+			},
+		}
+
+		result := Check(tool)
+
+		if result.Status != StatusFail {
+			t.Errorf("expected StatusFail, got %v", result.Status)
+		}
+		if len(result.Subcommands) != 2 {
+			t.Fatalf("expected 2 subcommand results, got %d", len(result.Subcommands))
+		}
+		if result.Subcommands[0].InstalledVersion == "" {
+			t.Error("expected subcommand installed version to be set")
+		}
+	})
+
+	t.Run("passes when only optional subcommands are missing", func(t *testing.T) {
+		tool := &config.Tool{
+			Name: "go",
+			CLI:  "go",
+			Subcommands: []config.Subcommand{
+				{Name: "nonexistent-subcommand", VersionArg: "version", Optional: true},
+			},
+		}
+
+		result := Check(tool)
+
+		if result.Status != StatusPass {
+			t.Errorf("expected StatusPass, got %v", result.Status)
+		}
+	})
+	t.Run("optional subcommands with the wrong version don't fail the tool", func(t *testing.T) {
+		tool := &config.Tool{
+			Name: "go",
+			CLI:  "go",
+			Subcommands: []config.Subcommand{
+				{Name: "env", VersionArg: "GOVERSION", Version: ">=999.0.0", Optional: true},
+			},
+		}
+
+		result := Check(tool)
+
+		if result.Status != StatusPass {
+			t.Errorf("expected StatusPass, got %v", result.Status)
+		}
+	})
+
+	t.Run("runs subcommands on the binary behind a shim", func(t *testing.T) {
+		shim, target := setupMiseShim(t)
+		t.Setenv("PATH", filepath.Dir(shim))
+		project := t.TempDir()
+		writeTestFile(t, filepath.Join(project, "mise.toml"), "[tools]\nfaketool = \"2.0.0\"\n")
+
+		tool := &config.Tool{
+			Name:        "faketool",
+			CLI:         "faketool",
+			Workdir:     project,
+			Subcommands: []config.Subcommand{{Name: "plugin", Version: ">=2.0.0"}},
+		}
+
+		result := Check(tool)
+
+		if result.Status != StatusPass {
+			t.Fatalf("expected StatusPass, got %v (error: %v)", result.Status, result.Error)
+		}
+		if cli := result.Subcommands[0].Tool.CLI; cli != target {
+			t.Errorf("expected the subcommand to run %q, got %q", target, cli)
+		}
+	})
+
+	t.Run("optional tool is degraded when a required subcommand is missing", func(t *testing.T) {
+		tool := &config.Tool{
+			Name:     "go",
+			CLI:      "go",
+			Optional: true,
+			Subcommands: []config.Subcommand{
+				{Name: "nonexistent-subcommand", VersionArg: "version"},
+			},
+		}
+
+		result := Check(tool)
+
+		if result.Status != StatusOptionalMissing {
+			t.Errorf("expected StatusOptionalMissing, got %v", result.Status)
+		}
+		if result.Subcommands[0].Status != StatusFail {
+			t.Errorf("expected the required subcommand to fail, got %v", result.Subcommands[0].Status)
+		}
+	})
+}
+
+func TestCheckSubcommandInheritsToolSettings(t *testing.T) {
+	allow := true
+	tests := []struct {
+		name string
+		tool config.Tool
+		sub  config.Subcommand
+	}{
+		{
+			name: "prerelease policy",
+			tool: config.Tool{AllowPrerelease: &allow},
+			sub: config.Subcommand{
+				VersionArg: `'echo 2.0.0-rc.1'`, VersionPattern: `(\d+\.\d+\.\d+-rc\.\d+)`, Version: ">=1.0.0",
+			},
+		},
+		{
+			name: "version scheme",
+			tool: config.Tool{VersionScheme: "loose"},
+			sub:  config.Subcommand{VersionArg: `'echo 1.2.3.4'`, VersionPattern: `([\d.]+)`, Version: ">=1.2.3.4"},
+		},
+		{
+			name: "environment",
+			tool: config.Tool{Env: map[string]string{"CHEX_SUB_VERSION": "1.2.3"}},
+			sub:  config.Subcommand{VersionArg: `'echo "$CHEX_SUB_VERSION"'`, Version: ">=1.2.3"},
+		},
+	}
+
+	t.Run("shell mode", func(t *testing.T) {
+		tool := &config.Tool{Name: "echo", CLI: "echo", Shell: true}
+		sub := config.Subcommand{Name: "1.2.3", VersionArg: "| tr 3 4", Version: ">=1.2.4"}
+
+		result := checkSubcommand(tool.CLI, tool, sub)
+
+		if result.Status != StatusPass {
+			t.Errorf("expected the version arguments to run through the shell, got %v (version %q, error: %v)",
+				result.Status, result.InstalledVersion, result.Error)
+		}
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tool.Name, tt.tool.CLI = "sh", "sh"
+			tt.sub.Name = "-c"
+
+			result := checkSubcommand(tt.tool.CLI, &tt.tool, tt.sub)
+
+			if result.Status != StatusPass {
+				t.Errorf("expected StatusPass, got %v (error: %v)", result.Status, result.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// shellOperators are words that only mean something to a shell. Outside shell
+// mode they're passed to the command as plain arguments.
+var shellOperators = []string{"|", "||", "&&", ";", "&", ">", ">>", "<", "2>&1"}
+
+// SplitArgs splits a command line into words the way a POSIX shell would, without
+// expanding anything: words are separated by whitespace, single quotes keep their
+// contents as is, double quotes allow \" and \\, and a backslash outside quotes
+// escapes the next character.
+func SplitArgs(s string) ([]string, error) {
+	var args []string
+	var word strings.Builder
+	inWord := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '\\':
+			if i+1 >= len(s) {
+				return nil, errors.New("trailing backslash")
+			}
+			i++
+			word.WriteByte(s[i])
+			inWord = true
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated ' in %q", s)
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '"':
+			n, err := readDoubleQuoted(s[i+1:], &word)
+			if err != nil {
+				return nil, fmt.Errorf("%w in %q", err, s)
+			}
+			i += n
+			inWord = true
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
+
+// readDoubleQuoted writes the contents of a double-quoted string to word and returns
+// how many bytes of s it used, including the closing quote.
+func readDoubleQuoted(s string, word *strings.Builder) (int, error) {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"':
+			return i + 1, nil
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`", s[i+1]) >= 0:
+			i++
+			word.WriteByte(s[i])
+		default:
+			word.WriteByte(c)
+		}
+	}
+	return 0, errors.New(`unterminated "`)
+}
+
+// JoinArgs joins words into a command line, quoting words that the shell would
+// otherwise split or interpret. SplitArgs(JoinArgs(args)) returns args.
+func JoinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = quoteArg(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// quoteArg single-quotes arg unless it only contains characters that are safe
+// unquoted.
+func quoteArg(arg string) string {
+	const safe = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=,+@%"
+	if arg != "" && strings.Trim(arg, safe) == "" {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
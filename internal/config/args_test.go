@@ -0,0 +1,61 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+		err      string
+	}{
+		{input: "", expected: nil},
+		{input: "  --version  ", expected: []string{"--version"}},
+		{input: "version --short", expected: []string{"version", "--short"}},
+		{input: `version --template '{{.Version}}'`, expected: []string{"version", "--template", "{{.Version}}"}},
+		{input: `-c "echo \"a b\" \$HOME \n"`, expected: []string{"-c", `echo "a b" $HOME \n`}},
+		{input: `a\ b c`, expected: []string{"a b", "c"}},
+		{input: `--name='' x`, expected: []string{"--name=", "x"}},
+		{input: `'' ""`, expected: []string{"", ""}},
+		{input: `pre'fix'"ed"`, expected: []string{"prefixed"}},
+		{input: "a\tb\nc", expected: []string{"a", "b", "c"}},
+		{input: "'open", err: "unterminated '"},
+		{input: `"open`, err: `unterminated "`},
+		{input: `trailing\`, err: "trailing backslash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			args, err := SplitArgs(tt.input)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("SplitArgs(%q) error = %v, expected %q", tt.input, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SplitArgs(%q) error = %v", tt.input, err)
+			}
+			if !slices.Equal(args, tt.expected) {
+				t.Errorf("SplitArgs(%q) = %q, expected %q", tt.input, args, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJoinArgs(t *testing.T) {
+	args := []string{"version", "--template", "{{.Version}}", "it's", "", "a b", "x=1,y/2"}
+
+	joined := JoinArgs(args)
+	if joined != `version --template '{{.Version}}' 'it'\''s' '' 'a b' x=1,y/2` {
+		t.Errorf("unexpected quoting: %s", joined)
+	}
+
+	split, err := SplitArgs(joined)
+	if err != nil || !slices.Equal(split, args) {
+		t.Errorf("SplitArgs(JoinArgs(args)) = %q, %v", split, err)
+	}
+}
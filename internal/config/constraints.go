@@ -0,0 +1,445 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Merge strategies for tools whose version is constrained by more than one source.
+const (
+	MergeFirstWins = "first-wins" // the first source's constraint is used (default)
+	MergeIntersect = "intersect"  // every source's constraint must be satisfied
+	MergeStrictest = "strictest"  // the constraint allowing the fewest versions is used
+)
+
+// SourceConstraint is a version constraint and the source that declared it.
+type SourceConstraint struct {
+	Version string
+	Source  string
+}
+
+// bound is one end of a version interval. A nil version is unbounded.
+type bound struct {
+	version   *semver.Version
+	inclusive bool
+}
+
+// interval is a contiguous range of versions.
+type interval struct {
+	lo, hi bound
+}
+
+var (
+	primitiveRe = regexp.MustCompile(`^(!=|>=|<=|=>|=<|>|<|=|~>|~|\^)?\s*v?([0-9xX*]+(?:\.[0-9xX*]+){0,2})$`)
+	hyphenRe    = regexp.MustCompile(`^v?([0-9xX*.]+)\s+-\s+v?([0-9xX*.]+)$`)
+)
+
+// recordConstraint notes another source's constraint for a tool that's already defined.
+func recordConstraint(tool *Tool, version, source string) {
+	if version == "" {
+		return
+	}
+	tool.Constraints = append(tool.Constraints, SourceConstraint{Version: version, Source: source})
+}
+
+// checkMergeStrategy reports an unknown merge_strategy, which then behaves as
+// first-wins. It's checked once per load, whether or not any tool has several
+// constraints to merge.
+func checkMergeStrategy(strategy string) []string {
+	if strategy == "" || slices.Contains(validMergeStrategies, strategy) {
+		return nil
+	}
+	return []string{fmt.Sprintf(
+		"Error: unknown merge_strategy '%s' (expected %s, %s or %s)",
+		strategy, MergeFirstWins, MergeIntersect, MergeStrictest,
+	)}
+}
+
+// mergeConstraints applies the merge strategy to a tool constrained by several sources,
+// returning a warning when their constraints have no version in common or can't be
+// compared. Other sources only narrow a version the tool's own definition sets, so a
+// tool defined without one stays an existence check.
+func mergeConstraints(tool *Tool, strategy string) []string {
+	ranges, err := constraintRanges(tool)
+	if err != nil {
+		if tool.Version == "" {
+			return nil
+		}
+		return []string{fmt.Sprintf(
+			"Warning: cannot merge version constraints for '%s': %v; using %s", tool.Name, err, tool.Version,
+		)}
+	}
+	if ranges == nil {
+		return nil
+	}
+
+	var warnings []string
+	if conflict := describeConflict(tool, ranges); conflict != "" {
+		warnings = append(warnings, "Warning: "+conflict)
+	}
+	if tool.Version == "" {
+		return warnings
+	}
+
+	switch strategy {
+	case MergeIntersect:
+		versions := make([]string, 0, len(tool.Constraints))
+		for _, constraint := range tool.Constraints {
+			versions = append(versions, constraint.Version)
+		}
+		tool.Version = joinConstraints(versions)
+	case MergeStrictest:
+		tool.Version = tool.Constraints[strictestRange(ranges)].Version
+	default:
+		// first-wins, which unknown strategies fall back to after checkMergeStrategy
+		tool.Version = tool.Constraints[0].Version
+	}
+
+	return warnings
+}
+
+// constraintRanges parses the constraints of a tool defined by several sources. It
+// returns nil when there's nothing to merge, and an error naming the constraint and
+// its source when one, such as "lts", isn't a version range.
+func constraintRanges(tool *Tool) ([][]interval, error) {
+	if len(tool.Constraints) < 2 {
+		return nil, nil
+	}
+	if tool.VersionScheme != "" && tool.VersionScheme != "semver" {
+		return nil, nil
+	}
+
+	ranges := make([][]interval, 0, len(tool.Constraints))
+	for _, constraint := range tool.Constraints {
+		r, err := parseRange(constraint.Version)
+		if err != nil {
+			return nil, fmt.Errorf("%q (%s) is not a version range", constraint.Version, constraint.Source)
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// describeConflict explains constraints that have no version in common, or returns
+// an empty string when they overlap.
+func describeConflict(tool *Tool, ranges [][]interval) string {
+	common := ranges[0]
+	for _, r := range ranges[1:] {
+		common = intersectRanges(common, r)
+	}
+	if len(common) > 0 {
+		return ""
+	}
+
+	described := make([]string, 0, len(tool.Constraints))
+	for _, constraint := range tool.Constraints {
+		described = append(described, fmt.Sprintf("%s (%s)", constraint.Version, constraint.Source))
+	}
+	return fmt.Sprintf(
+		"Conflicting version constraints for '%s': %s have no version in common",
+		tool.Name, strings.Join(described, ", "),
+	)
+}
+
+// joinConstraints ANDs constraints together, distributing "||" alternatives so
+// "a || b" and "c" become "a, c || b, c".
+func joinConstraints(constraints []string) string {
+	groups := []string{""}
+	for _, constraint := range constraints {
+		var next []string
+		for _, group := range groups {
+			for alternative := range strings.SplitSeq(constraint, "||") {
+				alternative = strings.TrimSpace(alternative)
+				if group != "" {
+					alternative = group + ", " + alternative
+				}
+				next = append(next, alternative)
+			}
+		}
+		groups = next
+	}
+	return strings.Join(groups, " || ")
+}
+
+// strictestRange returns the index of the range with the highest minimum version,
+// preferring the lowest maximum on ties.
+func strictestRange(ranges [][]interval) int {
+	strictest := 0
+	for i := 1; i < len(ranges); i++ {
+		lo, hi := rangeBounds(ranges[i])
+		bestLo, bestHi := rangeBounds(ranges[strictest])
+
+		if cmp := compareLower(lo, bestLo); cmp > 0 || (cmp == 0 && compareUpper(hi, bestHi) < 0) {
+			strictest = i
+		}
+	}
+	return strictest
+}
+
+// rangeBounds returns the overall lower and upper bound of a range.
+func rangeBounds(r []interval) (bound, bound) {
+	if len(r) == 0 {
+		return bound{}, bound{}
+	}
+	lo, hi := r[0].lo, r[0].hi
+	for _, in := range r[1:] {
+		if compareLower(in.lo, lo) < 0 {
+			lo = in.lo
+		}
+		if compareUpper(in.hi, hi) > 0 {
+			hi = in.hi
+		}
+	}
+	return lo, hi
+}
+
+// parseRange converts a semver constraint into a union of intervals, following
+// Masterminds semantics for partial versions (">1.2" means ">=1.3.0").
+// "!=" terms are ignored, so the result may be slightly wider than the constraint.
+func parseRange(constraint string) ([]interval, error) {
+	var union []interval
+	for alternative := range strings.SplitSeq(constraint, "||") {
+		r := []interval{{}}
+		for _, term := range splitTerms(alternative) {
+			in, err := parseTerm(term)
+			if err != nil {
+				return nil, err
+			}
+			r = intersectRanges(r, in)
+		}
+		union = append(union, r...)
+	}
+	return union, nil
+}
+
+// splitTerms splits an AND-ed constraint on commas and spaces, keeping operators
+// attached to their versions and hyphen ranges together.
+func splitTerms(constraint string) []string {
+	var terms []string
+	for part := range strings.SplitSeq(constraint, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if hyphenRe.MatchString(part) {
+			terms = append(terms, part)
+			continue
+		}
+
+		fields := strings.Fields(part)
+		for i := 0; i < len(fields); i++ {
+			// Join a lone operator with the version after it: ">= 1.2"
+			if strings.Trim(fields[i], "<>=!~^") == "" && i+1 < len(fields) {
+				fields[i+1] = fields[i] + fields[i+1]
+				continue
+			}
+			terms = append(terms, fields[i])
+		}
+	}
+	return terms
+}
+
+// parseTerm converts a single constraint term into intervals.
+func parseTerm(term string) ([]interval, error) {
+	if m := hyphenRe.FindStringSubmatch(term); m != nil {
+		from, err := parsePartial(m[1])
+		if err != nil {
+			return nil, err
+		}
+		to, err := parsePartial(m[2])
+		if err != nil {
+			return nil, err
+		}
+		return []interval{{lo: from.lower(), hi: to.upperInclusive()}}, nil
+	}
+
+	m := primitiveRe.FindStringSubmatch(term)
+	if m == nil {
+		return nil, fmt.Errorf("unsupported constraint %q", term)
+	}
+	p, err := parsePartial(m[2])
+	if err != nil {
+		return nil, err
+	}
+	if p.parts == 0 {
+		return []interval{{}}, nil
+	}
+
+	switch m[1] {
+	case "", "=":
+		return []interval{{lo: p.lower(), hi: p.upperInclusive()}}, nil
+	case "!=":
+		return []interval{{}}, nil
+	case ">":
+		return []interval{{lo: p.lowerExclusive()}}, nil
+	case ">=", "=>":
+		return []interval{{lo: p.lower()}}, nil
+	case "<":
+		return []interval{{hi: bound{version: p.floor()}}}, nil
+	case "<=", "=<":
+		return []interval{{hi: p.upperInclusive()}}, nil
+	case "~", "~>":
+		return []interval{{lo: p.lower(), hi: bound{version: p.tildeCeiling()}}}, nil
+	default: // "^"
+		return []interval{{lo: p.lower(), hi: bound{version: p.caretCeiling()}}}, nil
+	}
+}
+
+// partialVersion is a version with only its first parts specified, e.g. "1.2" or "1.x".
+type partialVersion struct {
+	numbers [3]uint64
+	parts   int // number of specified parts before any wildcard
+}
+
+// parsePartial parses a possibly partial version such as 20, 1.2, 1.2.x or 1.2.3.
+func parsePartial(text string) (partialVersion, error) {
+	var p partialVersion
+	for i, part := range strings.Split(text, ".") {
+		if i >= 3 {
+			return p, fmt.Errorf("too many version parts in %q", text)
+		}
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return p, fmt.Errorf("invalid version %q", text)
+		}
+		p.numbers[i] = n
+		p.parts = i + 1
+	}
+	return p, nil
+}
+
+// floor is the smallest version matching the partial version.
+func (p partialVersion) floor() *semver.Version {
+	return semver.New(p.numbers[0], p.numbers[1], p.numbers[2], "", "")
+}
+
+// next is the smallest version above every version matching the partial version.
+func (p partialVersion) next() *semver.Version {
+	numbers := p.numbers
+	numbers[p.parts-1]++
+	for i := p.parts; i < 3; i++ {
+		numbers[i] = 0
+	}
+	return semver.New(numbers[0], numbers[1], numbers[2], "", "")
+}
+
+// lower is the inclusive lower bound of the partial version.
+func (p partialVersion) lower() bound {
+	return bound{version: p.floor(), inclusive: true}
+}
+
+// lowerExclusive is the lower bound for ">p": above every version matching p.
+func (p partialVersion) lowerExclusive() bound {
+	if p.parts == 3 {
+		return bound{version: p.floor()}
+	}
+	return bound{version: p.next(), inclusive: true}
+}
+
+// upperInclusive is the upper bound for "<=p": every version matching p.
+func (p partialVersion) upperInclusive() bound {
+	if p.parts == 3 {
+		return bound{version: p.floor(), inclusive: true}
+	}
+	return bound{version: p.next()}
+}
+
+// tildeCeiling is the exclusive upper bound for "~p": the next minor, or the next
+// major when only the major is given.
+func (p partialVersion) tildeCeiling() *semver.Version {
+	if p.parts == 1 {
+		return semver.New(p.numbers[0]+1, 0, 0, "", "")
+	}
+	return semver.New(p.numbers[0], p.numbers[1]+1, 0, "", "")
+}
+
+// caretCeiling is the exclusive upper bound for "^p": the next increment of the
+// first non-zero part (or the last specified part when all are zero).
+func (p partialVersion) caretCeiling() *semver.Version {
+	for i := range p.parts {
+		if p.numbers[i] != 0 || i == p.parts-1 {
+			numbers := [3]uint64{}
+			copy(numbers[:i], p.numbers[:i])
+			numbers[i] = p.numbers[i] + 1
+			return semver.New(numbers[0], numbers[1], numbers[2], "", "")
+		}
+	}
+	return p.next()
+}
+
+// intersectRanges returns the versions in both a and b.
+func intersectRanges(a, b []interval) []interval {
+	var result []interval
+	for _, x := range a {
+		for _, y := range b {
+			in := interval{lo: x.lo, hi: x.hi}
+			if compareLower(y.lo, in.lo) > 0 {
+				in.lo = y.lo
+			}
+			if compareUpper(y.hi, in.hi) < 0 {
+				in.hi = y.hi
+			}
+			if !in.empty() {
+				result = append(result, in)
+			}
+		}
+	}
+	return slices.Clip(result)
+}
+
+// empty reports whether no version lies within the interval.
+func (in interval) empty() bool {
+	if in.lo.version == nil || in.hi.version == nil {
+		return false
+	}
+	cmp := in.lo.version.Compare(in.hi.version)
+	return cmp > 0 || (cmp == 0 && !(in.lo.inclusive && in.hi.inclusive))
+}
+
+// compareLower orders lower bounds; unbounded sorts first and exclusive sorts after inclusive.
+func compareLower(a, b bound) int {
+	switch {
+	case a.version == nil && b.version == nil:
+		return 0
+	case a.version == nil:
+		return -1
+	case b.version == nil:
+		return 1
+	}
+	if cmp := a.version.Compare(b.version); cmp != 0 {
+		return cmp
+	}
+	return boolRank(!a.inclusive) - boolRank(!b.inclusive)
+}
+
+// compareUpper orders upper bounds; unbounded sorts last and exclusive sorts before inclusive.
+func compareUpper(a, b bound) int {
+	switch {
+	case a.version == nil && b.version == nil:
+		return 0
+	case a.version == nil:
+		return 1
+	case b.version == nil:
+		return -1
+	}
+	if cmp := a.version.Compare(b.version); cmp != 0 {
+		return cmp
+	}
+	return boolRank(a.inclusive) - boolRank(b.inclusive)
+}
+
+// boolRank converts a bool to 0 or 1 for ordering.
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
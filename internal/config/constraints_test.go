@@ -0,0 +1,321 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConstraintsIntersect(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		disjoint bool
+	}{
+		{
+			name: "open ranges overlap",
+			a:    ">=18",
+			b:    "20",
+		},
+		{
+			name:     "exact version below minimum",
+			a:        ">=18",
+			b:        "16.20.2",
+			disjoint: true,
+		},
+		{
+			name: "partial version is a range",
+			a:    "20",
+			b:    "20.11.0",
+		},
+		{
+			name:     "different majors",
+			a:        "18",
+			b:        "20",
+			disjoint: true,
+		},
+		{
+			name: "caret and tilde overlap",
+			a:    "^1.2.0",
+			b:    "~1.9",
+		},
+		{
+			name:     "caret excludes next major",
+			a:        "^1.2.0",
+			b:        ">=2.0.0",
+			disjoint: true,
+		},
+		{
+			name:     "caret on zero major",
+			a:        "^0.2.3",
+			b:        "0.3.0",
+			disjoint: true,
+		},
+		{
+			name:     "exclusive bounds touch",
+			a:        "<1.5.0",
+			b:        ">=1.5.0",
+			disjoint: true,
+		},
+		{
+			name: "inclusive bounds touch",
+			a:    "<=1.5.0",
+			b:    ">=1.5.0",
+		},
+		{
+			name:     "partial greater than",
+			a:        ">1.2",
+			b:        "1.2.9",
+			disjoint: true,
+		},
+		{
+			name: "partial less or equal",
+			a:    "<=1.2",
+			b:    "1.2.9",
+		},
+		{
+			name: "hyphen range",
+			a:    "1.2 - 1.4",
+			b:    "1.4.5",
+		},
+		{
+			name:     "or alternatives",
+			a:        "<10 || >=20",
+			b:        "15",
+			disjoint: true,
+		},
+		{
+			name:     "and with spaces",
+			a:        ">= 1.0 < 2.0",
+			b:        "2.1",
+			disjoint: true,
+		},
+		{
+			name: "wildcard",
+			a:    "*",
+			b:    "1.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := parseRange(tt.a)
+			if err != nil {
+				t.Fatalf("parseRange(%q) error = %v", tt.a, err)
+			}
+			b, err := parseRange(tt.b)
+			if err != nil {
+				t.Fatalf("parseRange(%q) error = %v", tt.b, err)
+			}
+
+			if disjoint := len(intersectRanges(a, b)) == 0; disjoint != tt.disjoint {
+				t.Errorf("expected disjoint = %v, got %v", tt.disjoint, disjoint)
+			}
+		})
+	}
+}
+
+func TestMergeConstraints(t *testing.T) {
+	constraints := []SourceConstraint{
+		{Version: ">=18", Source: "config"},
+		{Version: "20", Source: "mise:mise.toml"},
+	}
+
+	tests := []struct {
+		name            string
+		strategy        string
+		constraints     []SourceConstraint
+		expectedVersion string
+		warning         string
+	}{
+		{
+			name:            "first wins by default",
+			constraints:     constraints,
+			expectedVersion: ">=18",
+		},
+		{
+			name:            "intersect",
+			strategy:        MergeIntersect,
+			constraints:     constraints,
+			expectedVersion: ">=18, 20",
+		},
+		{
+			name:     "intersect distributes alternatives",
+			strategy: MergeIntersect,
+			constraints: []SourceConstraint{
+				{Version: "18 || 20", Source: "config"},
+				{Version: ">=19", Source: "mise:mise.toml"},
+			},
+			expectedVersion: "18, >=19 || 20, >=19",
+		},
+		{
+			name:            "strictest",
+			strategy:        MergeStrictest,
+			constraints:     constraints,
+			expectedVersion: "20",
+		},
+		{
+			name:     "strictest prefers lower maximum on ties",
+			strategy: MergeStrictest,
+			constraints: []SourceConstraint{
+				{Version: ">=1.2.0", Source: "config"},
+				{Version: "^1.2.0", Source: "tool-versions:.tool-versions"},
+			},
+			expectedVersion: "^1.2.0",
+		},
+		{
+			name: "disjoint constraints warn",
+			constraints: []SourceConstraint{
+				{Version: ">=18", Source: "config"},
+				{Version: "16", Source: "mise:mise.toml"},
+			},
+			expectedVersion: ">=18",
+			warning:         "Conflicting version constraints for 'node': >=18 (config), 16 (mise:mise.toml)",
+		},
+		{
+			name:     "unparseable constraints are reported",
+			strategy: MergeIntersect,
+			constraints: []SourceConstraint{
+				{Version: ">=18", Source: "config"},
+				{Version: "lts", Source: "mise:mise.toml"},
+			},
+			expectedVersion: ">=18",
+			warning:         `cannot merge version constraints for 'node': "lts" (mise:mise.toml) is not a version range`,
+		},
+		{
+			name:     "unknown strategy falls back to first wins",
+			strategy: "newest",
+			constraints: []SourceConstraint{
+				{Version: "20", Source: "mise:mise.toml"},
+				{Version: ">=18", Source: "tool-versions:.tool-versions"},
+			},
+			expectedVersion: "20",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := &Tool{Name: "node", Version: tt.constraints[0].Version, Constraints: tt.constraints}
+
+			warnings := mergeConstraints(tool, tt.strategy)
+
+			if tool.Version != tt.expectedVersion {
+				t.Errorf("expected version %q, got %q", tt.expectedVersion, tool.Version)
+			}
+			if tt.warning == "" && len(warnings) > 0 {
+				t.Errorf("expected no warnings, got %v", warnings)
+			}
+			if tt.warning != "" && (len(warnings) != 1 || !strings.Contains(warnings[0], tt.warning)) {
+				t.Errorf("expected warning containing %q, got %v", tt.warning, warnings)
+			}
+		})
+	}
+}
+
+func TestLoadAndMergeConstraints(t *testing.T) {
+	t.Run("warns about conflicts with mise.toml", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		writeTestFile(t, configPath, `
+[node]
+cli = "node"
+version = ">=18"
+`)
+		writeTestFile(t, filepath.Join(tmpDir, "mise.toml"), `
+[tools]
+node = "16"
+`)
+
+		result := loadAndMergeHelper(t, configPath, tmpDir)
+
+		node := result.Tools["node"]
+		if len(node.Constraints) != 2 {
+			t.Fatalf("expected 2 constraints, got %+v", node.Constraints)
+		}
+		if node.Version != ">=18" {
+			t.Errorf("expected main config to win, got %q", node.Version)
+		}
+		if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "no version in common") {
+			t.Errorf("expected conflict warning, got %v", result.Warnings)
+		}
+	})
+
+	t.Run("reports an unknown merge_strategy without constraints to merge", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		writeTestFile(t, configPath, `
+[chex]
+merge_strategy = "intersection"
+
+[node]
+cli = "node"
+version = ">=18"
+
+[go]
+cli = "go"
+version = ">=1.22"
+`)
+
+		result := loadAndMergeHelper(t, configPath, tmpDir)
+
+		if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "unknown merge_strategy 'intersection'") {
+			t.Errorf("expected one unknown merge_strategy error, got %v", result.Warnings)
+		}
+		if version := result.Tools["node"].Version; version != ">=18" {
+			t.Errorf("expected the tool's own version, got %q", version)
+		}
+	})
+
+	t.Run("intersects with merge_strategy", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		writeTestFile(t, configPath, `
+[chex]
+merge_strategy = "intersect"
+
+[node]
+cli = "node"
+version = ">=18"
+`)
+		writeTestFile(t, filepath.Join(tmpDir, ".tool-versions"), "node 20.11.0\n")
+
+		result := loadAndMergeHelper(t, configPath, tmpDir)
+
+		if version := result.Tools["node"].Version; version != ">=18, 20.11.0" {
+			t.Errorf("expected intersected constraint, got %q", version)
+		}
+		if len(result.Warnings) != 0 {
+			t.Errorf("expected no warnings, got %v", result.Warnings)
+		}
+	})
+	t.Run("keeps tools without a version as existence checks", func(t *testing.T) {
+		for _, strategy := range []string{MergeFirstWins, MergeIntersect, MergeStrictest} {
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, ".chex.toml")
+
+			writeTestFile(t, configPath, fmt.Sprintf(`
+[chex]
+merge_strategy = %q
+
+[node]
+cli = "node"
+`, strategy))
+			writeTestFile(t, filepath.Join(tmpDir, "mise.toml"), "[tools]\nnode = \"20\"\n")
+			writeTestFile(t, filepath.Join(tmpDir, ".tool-versions"), "node 20.11.0\n")
+
+			result := loadAndMergeHelper(t, configPath, tmpDir)
+
+			if version := result.Tools["node"].Version; version != "" {
+				t.Errorf("%s: expected no version, got %q", strategy, version)
+			}
+			if len(result.Warnings) != 0 {
+				t.Errorf("%s: expected no warnings, got %v", strategy, result.Warnings)
+			}
+		}
+	})
+}
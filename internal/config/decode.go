@@ -0,0 +1,353 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// tomlLineError matches the line and key BurntSushi/toml reports for type mismatches.
+var tomlLineError = regexp.MustCompile(`^toml: line (\d+) \(last key "([^"]*)"\): (.*)$`)
+
+// decodeTOML decodes a .chex.toml file section by section, recording where every
+// key is defined and the order tools are declared in. Problems that stop the file
+// from loading, such as syntax errors and values of the wrong type, are returned
+// separately from the config, which is nil if the file doesn't parse at all.
+func decodeTOML(file string, data []byte) (*Config, []Diagnostic) {
+	d := &tomlDecoder{file: file}
+
+	var sections map[string]toml.Primitive
+	md, err := toml.Decode(string(data), &sections)
+	if err != nil {
+		pos := Position{Line: 1, Col: 1}
+		message := err.Error()
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			pos = Position{Line: parseErr.Position.Line, Col: parseErr.Position.Col}
+			message = parseErr.Message
+		}
+		d.add(pos, "%s", message)
+		return nil, d.problems
+	}
+
+	d.md = md
+	cfg := &Config{
+		File:      file,
+		Tools:     make(map[string]ToolConfig),
+		Env:       make(map[string]EnvConfig),
+		Files:     make(map[string]FileConfig),
+		Services:  make(map[string]ServiceConfig),
+		positions: scanKeyPositions(string(data)),
+	}
+	d.positions = cfg.positions
+
+	// Keys come in declaration order; a section may first appear through a dotted
+	// key or a sub-table header
+	seen := make(map[string]bool)
+	for _, key := range md.Keys() {
+		name := key[0]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		d.decodeSection(cfg, name, sections[name])
+	}
+
+	cfg.Unknown = append(d.unknownKeys(), d.unknownAlternativeKeys(cfg)...)
+	return cfg, d.problems
+}
+
+// tomlDecoder collects the problems found while decoding a TOML config file.
+type tomlDecoder struct {
+	file      string
+	md        toml.MetaData
+	positions keyPositions
+	problems  []Diagnostic
+}
+
+func (d *tomlDecoder) add(pos Position, format string, args ...any) {
+	d.problems = append(d.problems, Diagnostic{
+		File:     d.file,
+		Position: pos,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// decodeSection decodes a top-level section into its place in cfg. Sections that
+// fail to decode are left out.
+func (d *tomlDecoder) decodeSection(cfg *Config, name string, section toml.Primitive) {
+	switch name {
+	case "chex":
+		var chexCfg ChexConfig
+		if d.decode(section, &chexCfg, name) {
+			cfg.Chex = &chexCfg
+		}
+	case "env":
+		d.decode(section, &cfg.Env, name)
+	case "files":
+		d.decode(section, &cfg.Files, name)
+	case "services":
+		d.decode(section, &cfg.Services, name)
+	default:
+		var toolCfg ToolConfig
+		if d.decode(section, &toolCfg, name) {
+			cfg.Tools[name] = toolCfg
+			cfg.Order = append(cfg.Order, name)
+		}
+	}
+}
+
+func (d *tomlDecoder) decode(section toml.Primitive, to any, name string) bool {
+	err := d.md.PrimitiveDecode(section, to)
+	if err == nil {
+		return true
+	}
+
+	if m := tomlLineError.FindStringSubmatch(err.Error()); m != nil {
+		pos := d.positions.lookup(strings.Split(m[2], ".")...)
+		if line, _ := strconv.Atoi(m[1]); pos.Line != line {
+			pos = Position{Line: line, Col: 1}
+		}
+		d.add(pos, "%s: %s", m[2], m[3])
+		return false
+	}
+	d.add(d.positions.lookup(name), "[%s]: %v", name, err)
+	return false
+}
+
+// unknownKeys reports keys that no config struct has a field for, suggesting the
+// closest known key for typos.
+func (d *tomlDecoder) unknownKeys() []Diagnostic {
+	var unknown []Diagnostic
+	var reported []string
+	for _, key := range d.md.Undecoded() {
+		path := key.String()
+
+		// Keys inside an unknown table are covered by the table's diagnostic
+		if slices.ContainsFunc(reported, func(r string) bool {
+			return strings.HasPrefix(path, r+".")
+		}) {
+			continue
+		}
+		reported = append(reported, path)
+
+		message := fmt.Sprintf("unknown key %q", path)
+		if suggestion := suggestKey(key); suggestion != "" {
+			message += fmt.Sprintf(" (did you mean %q?)", suggestion)
+		}
+		unknown = append(unknown, Diagnostic{
+			File:     d.file,
+			Position: d.positions.lookup(key...),
+			Severity: SeverityError,
+			Message:  message,
+		})
+	}
+	return unknown
+}
+
+// unknownAlternativeKeys reports keys in alternative tables that don't match a
+// setting. Alternatives decode themselves, so the TOML metadata doesn't list them.
+func (d *tomlDecoder) unknownAlternativeKeys(cfg *Config) []Diagnostic {
+	var unknown []Diagnostic
+	for _, name := range cfg.Order {
+		for _, alt := range cfg.Tools[name].Alternatives {
+			for _, key := range slices.Sorted(slices.Values(alt.unknownKeys)) {
+				path := []string{name, "alternatives", key}
+				message := fmt.Sprintf("unknown key %q", strings.Join(path, "."))
+				if suggestion := suggestKey(path); suggestion != "" {
+					message += fmt.Sprintf(" (did you mean %q?)", suggestion)
+				}
+				unknown = append(unknown, Diagnostic{
+					File:     d.file,
+					Position: d.positions.lookup(path...),
+					Severity: SeverityError,
+					Message:  message,
+				})
+			}
+		}
+	}
+	return unknown
+}
+
+// yamlLineError matches the line yaml.v3 reports for syntax errors.
+var yamlLineError = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// decodeConfig decodes a config file in the format its extension names: .yaml,
+// .yml and .json files are decoded as YAML (a superset of JSON), anything else as
+// TOML. It also returns the document as plain values for checking against the schema.
+func decodeConfig(file string, data []byte) (*Config, map[string]any, []Diagnostic) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml", ".json":
+		return decodeYAML(file, data)
+	default:
+		cfg, problems := decodeTOML(file, data)
+		if cfg == nil {
+			return nil, nil, problems
+		}
+		var raw map[string]any
+		if err := toml.Unmarshal(data, &raw); err != nil {
+			return nil, nil, problems
+		}
+		return cfg, raw, problems
+	}
+}
+
+// decodeYAML decodes a .chex.yaml or .chex.json file. Sections are checked against
+// the schema's types, so problems point at the YAML source, then decoded through the
+// TOML decoder so every format shares one model.
+func decodeYAML(file string, data []byte) (*Config, map[string]any, []Diagnostic) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		pos, message := Position{Line: 1, Col: 1}, err.Error()
+		if m := yamlLineError.FindStringSubmatch(message); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			pos, message = Position{Line: line, Col: 1}, m[2]
+		}
+		return nil, nil, []Diagnostic{{File: file, Position: pos, Severity: SeverityError, Message: message}}
+	}
+
+	raw := make(map[string]any)
+	positions := keyPositions{}
+	var order []string
+	if len(root.Content) > 0 {
+		doc := root.Content[0]
+		if doc.Kind != yaml.MappingNode {
+			return nil, nil, []Diagnostic{{
+				File:     file,
+				Position: Position{Line: doc.Line, Col: doc.Column},
+				Severity: SeverityError,
+				Message:  "expected a mapping of sections at the top level",
+			}}
+		}
+		raw, _ = yamlValue(doc, "", positions).(map[string]any)
+		for i := 0; i < len(doc.Content); i += 2 {
+			name := doc.Content[i].Value
+			if !slices.Contains(order, name) {
+				order = append(order, name)
+			}
+			// A section with nothing under it is an empty table, as in TOML
+			if _, ok := raw[name]; !ok {
+				raw[name] = make(map[string]any)
+			}
+		}
+	}
+
+	var problems, unknown []Diagnostic
+	report := func(list *[]Diagnostic) func([]string, string, ...any) {
+		return func(path []string, format string, args ...any) {
+			*list = append(*list, Diagnostic{
+				File:     file,
+				Position: positions.lookup(path...),
+				Severity: SeverityError,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		}
+	}
+	// Enum values and required keys are left to validate and the checker, as for TOML
+	sv := &schemaValidator{
+		root: Schema(), report: report(&problems), reportUnknown: report(&unknown), structural: true,
+	}
+
+	// Leave out sections with problems, as the TOML decoder does
+	valid := make(map[string]any)
+	for _, name := range order {
+		before := len(problems)
+		sv.validate(sv.root.property(name), raw[name], []string{name})
+		if len(problems) == before {
+			valid[name] = raw[name]
+		}
+	}
+
+	encoded, err := toml.Marshal(valid)
+	if err != nil {
+		problems = append(problems, Diagnostic{
+			File: file, Position: Position{Line: 1, Col: 1}, Severity: SeverityError, Message: err.Error(),
+		})
+		return nil, nil, problems
+	}
+	cfg, tomlProblems := decodeTOML(file, encoded)
+	if cfg == nil {
+		return nil, nil, append(problems, tomlProblems...)
+	}
+
+	cfg.positions = positions
+	cfg.Unknown = unknown
+	cfg.Order = slices.DeleteFunc(order, func(name string) bool {
+		_, isTool := cfg.Tools[name]
+		return !isTool
+	})
+	return cfg, raw, problems
+}
+
+// yamlValue converts a YAML node to the plain values TOML decodes to, recording the
+// position of every key under path. Nulls convert to nil and are left out of
+// mappings and sequences.
+func yamlValue(node *yaml.Node, path string, positions keyPositions) any {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return yamlValue(node.Content[0], path, positions)
+	case yaml.AliasNode:
+		return yamlValue(node.Alias, path, positions)
+	case yaml.MappingNode:
+		mapping := make(map[string]any)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			keyPath := joinKey(path, key.Value)
+			if _, ok := positions[keyPath]; !ok {
+				positions[keyPath] = Position{Line: key.Line, Col: key.Column}
+			}
+			if value := yamlValue(node.Content[i+1], keyPath, positions); value != nil {
+				mapping[key.Value] = value
+			}
+		}
+		return mapping
+	case yaml.SequenceNode:
+		sequence := make([]any, 0, len(node.Content))
+		for _, item := range node.Content {
+			if value := yamlValue(item, path, positions); value != nil {
+				sequence = append(sequence, value)
+			}
+		}
+		return sequence
+	case yaml.ScalarNode:
+		return yamlScalar(node)
+	default:
+		return nil
+	}
+}
+
+// yamlScalar converts a scalar to a string, int64, float64 or bool like TOML's.
+// Timestamps stay strings, so date versions don't need quoting.
+func yamlScalar(node *yaml.Node) any {
+	switch node.ShortTag() {
+	case "!!null":
+		return nil
+	case "!!bool":
+		var b bool
+		if err := node.Decode(&b); err == nil {
+			return b
+		}
+	case "!!int":
+		var i int64
+		if err := node.Decode(&i); err == nil {
+			return i
+		}
+	case "!!float":
+		var f float64
+		if err := node.Decode(&f); err == nil {
+			return f
+		}
+	}
+	return node.Value
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// expandConfig expands variables in the settings that support them: a tool's cli,
+// version_arg, version_args and version (also on alternatives), env values and
+// workdir, source paths and file-check paths. Values that can't be expanded are
+// left as written and reported.
+func expandConfig(cfg *Config, rootDir string) []Diagnostic {
+	var problems []Diagnostic
+	expand := func(value *string, path ...string) {
+		expanded, err := expandValue(*value, rootDir)
+		if err != nil {
+			problems = append(problems, Diagnostic{
+				File:     cfg.File,
+				Position: cfg.Position(path...),
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("%s: %v", strings.Join(path, "."), err),
+				keys:     path,
+			})
+			return
+		}
+		*value = expanded
+	}
+
+	if cfg.Chex != nil {
+		for i := range cfg.Chex.Sources {
+			expand(&cfg.Chex.Sources[i].Path, "chex", "sources", "path")
+		}
+	}
+
+	for _, name := range cfg.Order {
+		tool := cfg.Tools[name]
+		expand(&tool.CLI, name, "cli")
+		expand(&tool.VersionArg, name, "version_arg")
+		tool.VersionArgs = slices.Clone(tool.VersionArgs)
+		for i := range tool.VersionArgs {
+			expand(&tool.VersionArgs[i], name, "version_args")
+		}
+		expand(&tool.Version, name, "version")
+		expand(&tool.Workdir, name, "workdir")
+		tool.Env = maps.Clone(tool.Env)
+		for _, key := range slices.Sorted(maps.Keys(tool.Env)) {
+			value := tool.Env[key]
+			expand(&value, name, "env", key)
+			tool.Env[key] = value
+		}
+		tool.Alternatives = slices.Clone(tool.Alternatives)
+		for i := range tool.Alternatives {
+			expand(&tool.Alternatives[i].CLI, name, "alternatives", "cli")
+			expand(&tool.Alternatives[i].VersionArg, name, "alternatives", "version_arg")
+			expand(&tool.Alternatives[i].Version, name, "alternatives", "version")
+		}
+		cfg.Tools[name] = tool
+	}
+
+	for name, file := range cfg.Files {
+		expand(&file.Path, "files", name, "path")
+		expand(&file.NewerThan, "files", name, "newer_than")
+		cfg.Files[name] = file
+	}
+
+	slices.SortStableFunc(problems, func(a, b Diagnostic) int {
+		return a.Position.compare(b.Position)
+	})
+	return problems
+}
+
+// expandValue expands a leading ~ and ${...} references in value:
+//
+//	${NAME}, ${env:NAME}  the environment variable NAME, which must be set
+//	${project_root}       the absolute project root
+//	${file:PATH}          the first line of PATH, relative to the project root
+//
+// $${ is written as a literal ${.
+func expandValue(value, rootDir string) (string, error) {
+	if value == "~" || strings.HasPrefix(value, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot expand ~: %w", err)
+		}
+		value = home + value[1:]
+	}
+
+	original := value
+	var b strings.Builder
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			b.WriteString(value)
+			return b.String(), nil
+		}
+		if start > 0 && value[start-1] == '$' {
+			b.WriteString(value[:start-1] + "${")
+			value = value[start+2:]
+			continue
+		}
+
+		end := strings.IndexByte(value[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unclosed \"${\" in %q", original)
+		}
+		expanded, err := expandReference(value[start+2:start+end], rootDir)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(value[:start] + expanded)
+		value = value[start+end+1:]
+	}
+}
+
+// expandReference returns the value of the reference inside ${...}.
+func expandReference(ref, rootDir string) (string, error) {
+	kind, arg, hasKind := strings.Cut(ref, ":")
+	if !hasKind {
+		kind, arg = "env", ref
+		if ref == "project_root" {
+			kind = "project_root"
+		}
+	}
+
+	switch kind {
+	case "env":
+		if arg == "" {
+			return "", errors.New("empty variable name in \"${}\"")
+		}
+		if value, ok := os.LookupEnv(arg); ok {
+			return value, nil
+		}
+		if arg == "HOME" {
+			if home, err := os.UserHomeDir(); err == nil {
+				return home, nil
+			}
+		}
+		return "", fmt.Errorf("undefined variable %q", arg)
+	case "project_root":
+		return filepath.Abs(rootDir)
+	case "file":
+		return readFirstLine(resolvePath(arg, rootDir))
+	default:
+		return "", fmt.Errorf("unknown function %q in \"${%s}\" (expected env or file)", kind, ref)
+	}
+}
+
+// readFirstLine returns the first non-blank line of a file such as .go-version or
+// .nvmrc, without surrounding whitespace.
+func readFirstLine(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			return line, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	return "", fmt.Errorf("%s is empty", path)
+}
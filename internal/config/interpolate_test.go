@@ -0,0 +1,177 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandValue(t *testing.T) {
+	rootDir := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("JAVA_HOME", "/opt/java")
+	t.Setenv("CHEX_EMPTY", "")
+	writeTestFile(t, filepath.Join(rootDir, ".go-version"), "\n1.22.3\n")
+	writeTestFile(t, filepath.Join(rootDir, "empty"), "\n")
+
+	tests := []struct {
+		value    string
+		expected string
+		err      string
+	}{
+		{value: "go", expected: "go"},
+		{value: "${JAVA_HOME}/bin/java", expected: "/opt/java/bin/java"},
+		{value: "${env:JAVA_HOME}/bin/java", expected: "/opt/java/bin/java"},
+		{value: "${HOME}/.local/bin/tool", expected: home + "/.local/bin/tool"},
+		{value: "~/.local/bin/tool", expected: home + "/.local/bin/tool"},
+		{value: "~", expected: home},
+		{value: "a~b", expected: "a~b"},
+		{value: "${project_root}/bin/tool", expected: rootDir + "/bin/tool"},
+		{value: ">=${file:.go-version}", expected: ">=1.22.3"},
+		{value: "x${CHEX_EMPTY}y", expected: "xy"},
+		{value: "$${JAVA_HOME} $HOME", expected: "${JAVA_HOME} $HOME"},
+		{value: "${CHEX_UNDEFINED_VARIABLE}/bin", err: `undefined variable "CHEX_UNDEFINED_VARIABLE"`},
+		{value: "${env:CHEX_UNDEFINED_VARIABLE}", err: `undefined variable "CHEX_UNDEFINED_VARIABLE"`},
+		{value: "${}", err: "empty variable name"},
+		{value: "${JAVA_HOME", err: `unclosed "${"`},
+		{value: "${shell:ls}", err: `unknown function "shell"`},
+		{value: "${file:missing}", err: "cannot read"},
+		{value: "${file:empty}", err: "is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := expandValue(tt.value, rootDir)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("expandValue(%q) error = %v, expected %q", tt.value, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandValue(%q) error = %v", tt.value, err)
+			}
+			if got != tt.expected {
+				t.Errorf("expandValue(%q) = %q, expected %q", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpandConfig(t *testing.T) {
+	rootDir := t.TempDir()
+	t.Setenv("JAVA_HOME", "/opt/java")
+	writeTestFile(t, filepath.Join(rootDir, ".nvmrc"), "20.11.0\n")
+	writeTestFile(t, filepath.Join(rootDir, ".chex.toml"), `
+[chex]
+sources = [{ path = "${project_root}/tools.toml", type = "chex" }]
+
+[java]
+cli = "${JAVA_HOME}/bin/java"
+version_arg = "-version"
+
+[node]
+cli = "node"
+version = "${file:.nvmrc}"
+alternatives = [{ cli = "${JAVA_HOME}/bin/node" }]
+env = { NODE_OPTIONS = "--require ${project_root}/hook.js" }
+workdir = "web"
+
+[files.jdk]
+path = "${env:JAVA_HOME}/release"
+`)
+	writeTestFile(t, filepath.Join(rootDir, "tools.toml"), `
+[go]
+cli = "${project_root}/bin/go"
+`)
+
+	result, err := LoadAndMerge("", rootDir)
+	if err != nil {
+		t.Fatalf("LoadAndMerge() error = %v", err)
+	}
+
+	if cli := result.Tools["java"].CLI; cli != "/opt/java/bin/java" {
+		t.Errorf("unexpected java cli %q", cli)
+	}
+	node := result.Tools["node"]
+	if node.Version != "20.11.0" || node.Constraints[0].Version != "20.11.0" {
+		t.Errorf("expected node version from .nvmrc, got %q %v", node.Version, node.Constraints)
+	}
+	if node.Env["NODE_OPTIONS"] != "--require "+rootDir+"/hook.js" || node.Workdir != filepath.Join(rootDir, "web") {
+		t.Errorf("unexpected node env %v and workdir %q", node.Env, node.Workdir)
+	}
+	if node.Alternatives[0].CLI != "/opt/java/bin/node" {
+		t.Errorf("unexpected alternative cli %q", node.Alternatives[0].CLI)
+	}
+	if cli := result.Tools["go"].CLI; cli != filepath.Join(rootDir, "bin", "go") {
+		t.Errorf("expected go from the expanded source path, got %q", cli)
+	}
+	if path := result.Files["jdk"].Path; path != "/opt/java/release" {
+		t.Errorf("unexpected file path %q", path)
+	}
+
+	t.Run("fails only the tool with undefined variables", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), ".chex.toml")
+		writeTestFile(t, configPath, `[java]
+cli = "${CHEX_UNDEFINED_VARIABLE}/bin/java"
+
+[go]
+cli = "go"
+
+[files.jdk]
+path = "${CHEX_UNDEFINED_VARIABLE}/release"
+`)
+
+		result, err := LoadAndMerge(configPath, "")
+		if err != nil {
+			t.Fatalf("LoadAndMerge() error = %v", err)
+		}
+		expected := `2:1: java.cli: undefined variable "CHEX_UNDEFINED_VARIABLE"`
+		if err := result.Tools["java"].ConfigError; err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected undefined variable error on java, got %v", err)
+		}
+		if err := result.Tools["go"].ConfigError; err != nil {
+			t.Errorf("expected go to be unaffected, got %v", err)
+		}
+		if err := result.Files["jdk"].ConfigError; err == nil || !strings.Contains(err.Error(), "files.jdk.path") {
+			t.Errorf("expected undefined variable error on the jdk file, got %v", err)
+		}
+		if len(result.Warnings) != 0 {
+			t.Errorf("expected no warnings, got %v", result.Warnings)
+		}
+	})
+
+	t.Run("fails tools from sources with undefined variables", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tmpDir, ".chex.toml"), `
+[chex]
+sources = [{ path = "tools.toml", type = "chex" }]
+`)
+		writeTestFile(t, filepath.Join(tmpDir, "tools.toml"), "[go]\ncli = \"${CHEX_UNDEFINED_VARIABLE}/go\"\n")
+
+		result, err := LoadAndMerge("", tmpDir)
+		if err != nil {
+			t.Fatalf("LoadAndMerge() error = %v", err)
+		}
+		if err := result.Tools["go"].ConfigError; err == nil || !strings.Contains(err.Error(), "go.cli: undefined variable") {
+			t.Errorf("expected undefined variable error on go, got %v", err)
+		}
+	})
+
+	t.Run("warns about source paths with undefined variables", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeTestFile(t, filepath.Join(tmpDir, ".chex.toml"), `
+[chex]
+sources = [{ path = "${CHEX_UNDEFINED_VARIABLE}/tools.toml", type = "chex" }]
+`)
+
+		result, err := LoadAndMerge("", tmpDir)
+		if err != nil {
+			t.Fatalf("LoadAndMerge() error = %v", err)
+		}
+		if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "chex.sources.path: undefined variable") {
+			t.Errorf("expected undefined variable warning, got %v", result.Warnings)
+		}
+	})
+}
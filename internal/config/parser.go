@@ -2,19 +2,25 @@ package config
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/BurntSushi/toml"
 )
 
-// Load loads and parses the chex configuration from the specified path.
-// If path is empty, it searches for .chex.toml in the current directory.
+// Load loads and parses the chex configuration from the specified path, which may
+// be a TOML, YAML or JSON file. If path is empty, it searches the current directory
+// for .chex.toml, .chex.yaml, .chex.yml and .chex.json, in that order.
+// Keys that don't match any setting are reported in Config.Unknown rather than
+// failing the load.
 func Load(path string) (*Config, error) {
 	if path == "" {
-		path = ".chex.toml"
+		path = findConfig(".")
 	}
 
 	data, err := os.ReadFile(path)
@@ -22,85 +28,78 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	// First decode into a generic map to get all sections
-	var raw map[string]any
-	if err := toml.Unmarshal(data, &raw); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
-	}
-
-	cfg := &Config{
-		Tools: make(map[string]ToolConfig),
-	}
-
-	// Process each section
-	for name, value := range raw {
-		if name == "chex" {
-			// Parse chex config section
-			var chexCfg ChexConfig
-			err := decodeInto(value, &chexCfg)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse [chex] section: %w", err)
-			}
-			cfg.Chex = &chexCfg
-		} else {
-			// Parse as tool config
-			var toolCfg ToolConfig
-			err := decodeInto(value, &toolCfg)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse [%s] section: %w", name, err)
-			}
-			cfg.Tools[name] = toolCfg
-		}
+	cfg, _, problems := decodeConfig(path, data)
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("failed to parse config file: %s", problems[0])
 	}
 
 	return cfg, nil
 }
 
-// decodeInto decodes a generic interface{} into a target struct.
-func decodeInto(from any, to any) error {
-	// Convert to TOML and back to decode properly
-	data, err := toml.Marshal(from)
-	if err != nil {
-		return err
-	}
-	return toml.Unmarshal(data, to)
-}
-
 // LoadResult contains the loaded tools and any warnings.
 type LoadResult struct {
 	Tools    map[string]*Tool
+	Env      map[string]*EnvRequirement
+	Files    map[string]*FileRequirement
+	Services map[string]*ServiceRequirement
+	Order    []string // tool names in the order they're declared, main config first
 	Warnings []string
 }
 
 // LoadAndMerge loads the main config and merges external sources.
 func LoadAndMerge(path, rootDir string) (*LoadResult, error) {
-	if path == "" {
-		path = ".chex.toml"
-	}
-	if rootDir == "" {
-		rootDir = "."
-	}
-
 	// Load main config - only use rootDir if path is relative
-	configPath := path
-	if !filepath.IsAbs(path) {
-		configPath = filepath.Join(rootDir, path)
-	}
+	configPath, rootDir := configLocation(path, rootDir)
 	cfg, err := Load(configPath)
 	if err != nil {
 		return nil, err
 	}
+	problems := expandConfig(cfg, rootDir)
 
 	result := &LoadResult{
 		Tools:    make(map[string]*Tool),
+		Env:      make(map[string]*EnvRequirement),
+		Files:    make(map[string]*FileRequirement),
+		Services: make(map[string]*ServiceRequirement),
 		Warnings: []string{},
 	}
 
+	// Keys that don't match any setting are most likely typos
+	for _, unknown := range cfg.Unknown {
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"Warning: %s:%d:%d: %s", unknown.File, unknown.Line, unknown.Col, unknown.Message,
+		))
+	}
+
 	// Convert config tools to Tool structs
-	for name, toolCfg := range cfg.Tools {
-		tool := configToTool(name, toolCfg, "config")
+	for _, name := range cfg.Order {
+		tool := configToTool(name, cfg.Tools[name], "config")
+		tool.File = cfg.File
+		tool.Position = cfg.Position(name)
 		result.Tools[name] = &tool
 	}
+	result.Order = slices.Clone(cfg.Order)
+
+	// Convert environment variable requirements
+	for name, envCfg := range cfg.Env {
+		env := configToEnv(name, envCfg)
+		result.Env[name] = &env
+	}
+
+	// Convert file requirements
+	for name, fileCfg := range cfg.Files {
+		file := configToFile(name, fileCfg, rootDir)
+		result.Files[name] = &file
+	}
+
+	// Convert service requirements
+	for name, serviceCfg := range cfg.Services {
+		service := configToService(name, serviceCfg, rootDir)
+		result.Services[name] = &service
+	}
+
+	// Values that can't be expanded only fail the tool or requirement they belong to
+	result.Warnings = append(result.Warnings, attachProblems(problems, result.Tools, result.Files)...)
 
 	// Determine behavior for unknown tools
 	failOnUnknown := cfg.Chex != nil && cfg.Chex.FailOnUnknownTools
@@ -119,31 +118,140 @@ func LoadAndMerge(path, rootDir string) (*LoadResult, error) {
 			warnings := loadSource(
 				sourcePath,
 				source.Type,
+				rootDir,
 				result.Tools,
 				failOnUnknown,
 				skipUnknown,
 				warnOnUnknown,
 			)
 			result.Warnings = append(result.Warnings, warnings...)
+			result.Order = appendNewTools(result.Order, result.Tools)
 		}
 	} else {
 		// Auto-detect mise.toml and .tool-versions (always relative to rootDir)
 		misePath := filepath.Join(rootDir, "mise.toml")
 		if _, err := os.Stat(misePath); err == nil {
-			warnings := loadSource(misePath, "mise", result.Tools, failOnUnknown, skipUnknown, warnOnUnknown)
+			warnings := loadSource(misePath, SourceMise, rootDir, result.Tools, failOnUnknown, skipUnknown, warnOnUnknown)
 			result.Warnings = append(result.Warnings, warnings...)
+			result.Order = appendNewTools(result.Order, result.Tools)
 		}
 
 		toolVersionsPath := filepath.Join(rootDir, ".tool-versions")
 		if _, err := os.Stat(toolVersionsPath); err == nil {
-			warnings := loadSource(toolVersionsPath, "tool-versions", result.Tools, failOnUnknown, skipUnknown, warnOnUnknown)
+			warnings := loadSource(
+				toolVersionsPath,
+				SourceToolVersions,
+				rootDir,
+				result.Tools,
+				failOnUnknown,
+				skipUnknown,
+				warnOnUnknown,
+			)
 			result.Warnings = append(result.Warnings, warnings...)
+			result.Order = appendNewTools(result.Order, result.Tools)
 		}
 	}
 
+	// Merge constraints in name order so warnings are stable
+	strategy := ""
+	if cfg.Chex != nil {
+		strategy = cfg.Chex.MergeStrategy
+	}
+	result.Warnings = append(result.Warnings, checkMergeStrategy(strategy)...)
+	for _, name := range slices.Sorted(maps.Keys(result.Tools)) {
+		tool := result.Tools[name]
+		resolveToolPaths(tool, rootDir)
+		applyToolDefaults(tool, cfg.Chex)
+		result.Warnings = append(result.Warnings, mergeConstraints(tool, strategy)...)
+	}
+
 	return result, nil
 }
 
+// attachProblems records each expansion problem on the tool or file requirement it
+// belongs to, so it's reported when that requirement is checked, and returns the
+// rest as warnings.
+func attachProblems(problems []Diagnostic, tools map[string]*Tool, files map[string]*FileRequirement) []string {
+	var warnings []string
+	for _, problem := range problems {
+		err := fmt.Errorf("%s:%d:%d: %s", problem.File, problem.Line, problem.Col, problem.Message)
+
+		if problem.keys[0] == "files" {
+			if file, ok := files[problem.keys[1]]; ok {
+				file.ConfigError = errors.Join(file.ConfigError, err)
+				continue
+			}
+		}
+		if tool, ok := tools[problem.keys[0]]; ok {
+			tool.ConfigError = errors.Join(tool.ConfigError, err)
+			continue
+		}
+
+		warnings = append(warnings, "Error: "+err.Error())
+	}
+	return warnings
+}
+
+// configLocation applies the defaults LoadAndMerge uses for the config path and root.
+func configLocation(path, rootDir string) (string, string) {
+	if rootDir == "" {
+		rootDir = "."
+	}
+	if path == "" {
+		path = findConfig(rootDir)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(rootDir, path)
+	}
+	return path, rootDir
+}
+
+// configFileNames are the config files looked for when none is given, in order.
+var configFileNames = []string{".chex.toml", ".chex.yaml", ".chex.yml", ".chex.json"}
+
+// findConfig returns the name of the first config file present in dir, or
+// .chex.toml if there are none.
+func findConfig(dir string) string {
+	if name := ExistingConfig(dir); name != "" {
+		return name
+	}
+	return configFileNames[0]
+}
+
+// ExistingConfig returns the name of the first config file present in dir, or ""
+// if there are none.
+func ExistingConfig(dir string) string {
+	for _, name := range configFileNames {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// appendNewTools appends the tools a source added to order, in the order the source
+// defines them.
+func appendNewTools(order []string, tools map[string]*Tool) []string {
+	var added []string
+	for name := range tools {
+		if !slices.Contains(order, name) {
+			added = append(added, name)
+		}
+	}
+	slices.SortFunc(added, func(a, b string) int {
+		return tools[a].Position.compare(tools[b].Position)
+	})
+	return append(order, added...)
+}
+
+// applyToolDefaults fills tool settings left unset with the [chex] defaults.
+func applyToolDefaults(tool *Tool, chex *ChexConfig) {
+	if tool.AllowPrerelease == nil {
+		allow := chex != nil && chex.AllowPrerelease
+		tool.AllowPrerelease = &allow
+	}
+}
+
 // configToTool converts a ToolConfig to a Tool.
 func configToTool(name string, cfg ToolConfig, source string) Tool {
 	displayName := name
@@ -154,57 +262,185 @@ func configToTool(name string, cfg ToolConfig, source string) Tool {
 	// Don't set a default version arg - let smart guessing handle it
 	versionArg := cfg.VersionArg
 
+	var constraints []SourceConstraint
+	if cfg.Version != "" {
+		constraints = []SourceConstraint{{Version: cfg.Version, Source: source}}
+	}
+
 	return Tool{
-		Name:           displayName,
-		CLI:            cfg.CLI,
-		Version:        cfg.Version,
-		VersionArg:     versionArg,
-		VersionPattern: cfg.VersionPattern,
-		Optional:       cfg.Optional,
-		Message:        cfg.Message,
-		Source:         source,
+		Name:            displayName,
+		CLI:             cfg.CLI,
+		Version:         cfg.Version,
+		VersionArg:      versionArg,
+		VersionArgs:     cfg.VersionArgs,
+		Shell:           cfg.Shell,
+		Env:             cfg.Env,
+		ClearEnv:        cfg.ClearEnv,
+		Workdir:         cfg.Workdir,
+		VersionPattern:  cfg.VersionPattern,
+		VersionSource:   cfg.VersionSource,
+		VersionJSON:     cfg.VersionJSON,
+		VersionYAML:     cfg.VersionYAML,
+		VersionScheme:   cfg.VersionScheme,
+		VersionPreset:   cfg.VersionPreset,
+		VersionReplace:  cfg.VersionReplace,
+		VersionCoerce:   cfg.VersionCoerce,
+		AllowPrerelease: cfg.AllowPrerelease,
+		Constraints:     constraints,
+		Optional:        cfg.Optional,
+		Message:         cfg.Message,
+		Source:          source,
+		When:            cfg.When,
+		Alternatives:    cfg.Alternatives,
+		Checks:          cfg.Checks,
+		Subcommands:     append(slices.Clone(cfg.Subcommands), cfg.Plugins...),
+		PathPattern:     cfg.PathPattern,
+		ManagedBy:       cfg.ManagedBy,
+		SHA256:          cfg.SHA256,
+		SHA256File:      cfg.SHA256File,
+		Arch:            cfg.Arch,
+		Static:          cfg.Static,
+	}
+}
+
+// configToEnv converts an EnvConfig to an EnvRequirement.
+func configToEnv(name string, cfg EnvConfig) EnvRequirement {
+	displayName := name
+	if cfg.Name != "" {
+		displayName = cfg.Name
+	}
+
+	return EnvRequirement{
+		Name:     displayName,
+		Variable: name,
+		Pattern:  cfg.Pattern,
+		IsDir:    cfg.IsDir,
+		Optional: cfg.Optional,
+		Message:  cfg.Message,
+	}
+}
+
+// configToFile converts a FileConfig to a FileRequirement, resolving its paths against rootDir.
+func configToFile(name string, cfg FileConfig, rootDir string) FileRequirement {
+	displayName := name
+	if cfg.Name != "" {
+		displayName = cfg.Name
+	}
+
+	file := FileRequirement{
+		Name:     displayName,
+		Path:     resolvePath(cfg.Path, rootDir),
+		Exists:   cfg.Exists == nil || *cfg.Exists,
+		Mode:     cfg.Mode,
+		Contains: cfg.Contains,
+		Optional: cfg.Optional,
+		Message:  cfg.Message,
+	}
+	if cfg.NewerThan != "" {
+		file.NewerThan = resolvePath(cfg.NewerThan, rootDir)
+	}
+
+	return file
+}
+
+// configToService converts a ServiceConfig to a ServiceRequirement.
+func configToService(name string, cfg ServiceConfig, rootDir string) ServiceRequirement {
+	displayName := name
+	if cfg.Name != "" {
+		displayName = cfg.Name
 	}
+
+	return ServiceRequirement{
+		Name:       displayName,
+		Address:    cfg.Address,
+		Socket:     resolvePath(cfg.Socket, rootDir),
+		Timeout:    cfg.Timeout,
+		HTTPPath:   cfg.HTTPPath,
+		HTTPStatus: cfg.HTTPStatus,
+		Optional:   cfg.Optional,
+		Message:    cfg.Message,
+	}
+}
+
+// resolveToolPaths makes relative paths referenced by a tool absolute to rootDir.
+func resolveToolPaths(tool *Tool, rootDir string) {
+	if tool.When != nil && tool.When.FileExists != "" {
+		when := *tool.When
+		when.FileExists = resolvePath(when.FileExists, rootDir)
+		tool.When = &when
+	}
+	tool.SHA256File = resolvePath(tool.SHA256File, rootDir)
+	tool.Workdir = resolvePath(tool.Workdir, rootDir)
+}
+
+// resolvePath expands a leading ~ to the home directory and makes relative paths
+// absolute to rootDir.
+func resolvePath(path, rootDir string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(rootDir, path)
 }
 
 // loadSource loads tools from an external source and merges them into the tools map.
-// It doesn't override tools that are already defined in the main config.
-// Returns warnings about unknown tools.
+// It doesn't override tools that are already defined in the main config, but records
+// their constraints so LoadAndMerge can check them against each other.
+// Returns warnings about unknown tools and values that couldn't be expanded.
 func loadSource(
-	path, sourceType string,
+	path, sourceType, rootDir string,
 	tools map[string]*Tool,
 	failOnUnknown, skipUnknown, warnOnUnknown bool,
 ) []string {
 	switch sourceType {
-	case "chex":
+	case SourceChex:
 		// chex sources don't have unknown tools
-		_ = loadChexSource(path, tools)
-		return nil
-	case "mise":
+		warnings, _ := loadChexSource(path, rootDir, tools)
+		return warnings
+	case SourceMise:
 		return loadMiseSource(path, tools, failOnUnknown, skipUnknown, warnOnUnknown)
-	case "tool-versions":
+	case SourceToolVersions:
 		return loadToolVersionsSource(path, tools, failOnUnknown, skipUnknown, warnOnUnknown)
 	default:
 		return []string{"Error: unknown source type: " + sourceType}
 	}
 }
 
-// loadChexSource loads tools from another chex config file.
-func loadChexSource(path string, tools map[string]*Tool) error {
+// loadChexSource loads tools from another chex config file, expanding variables
+// relative to the project root. Values that can't be expanded fail the tools the
+// source adds, like in the main config; for tools it doesn't add they're returned
+// as warnings.
+func loadChexSource(path, rootDir string, tools map[string]*Tool) ([]string, error) {
 	cfg, err := Load(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	for name, toolCfg := range cfg.Tools {
-		// Don't override existing tools
-		if _, exists := tools[name]; exists {
+	problems := expandConfig(cfg, rootDir)
+
+	added := make(map[string]*Tool)
+	for _, name := range cfg.Order {
+		toolCfg := cfg.Tools[name]
+		// Don't override existing tools, but note their constraints for merging
+		if existing, exists := tools[name]; exists {
+			if !slices.ContainsFunc(problems, func(d Diagnostic) bool {
+				return slices.Equal(d.keys, []string{name, "version"})
+			}) {
+				recordConstraint(existing, toolCfg.Version, "chex:"+path)
+			}
 			continue
 		}
 		tool := configToTool(name, toolCfg, "chex:"+path)
+		tool.File = path
+		tool.Position = cfg.Position(name)
 		tools[name] = &tool
+		added[name] = &tool
 	}
 
-	return nil
+	return attachProblems(problems, added, nil), nil
 }
 
 // loadMiseSource loads tools from a mise.toml file.
@@ -229,16 +465,18 @@ func loadMiseSource(
 	if err := toml.Unmarshal(data, &miseCfg); err != nil {
 		return []string{fmt.Sprintf("Error parsing mise.toml: %v", err)}
 	}
+	positions := scanKeyPositions(string(data))
 
 	for name, value := range miseCfg.Tools {
-		// Don't override existing tools from main config
-		if _, exists := tools[name]; exists {
-			continue
-		}
-
 		// Extract version from various mise.toml formats
 		version := extractMiseVersion(value)
 
+		// Don't override existing tools from main config, but note their constraints for merging
+		if existing, exists := tools[name]; exists {
+			recordConstraint(existing, version, "mise:"+path)
+			continue
+		}
+
 		// Resolve tool mapping
 		cli, versionArg, known := resolveToolMapping(name)
 
@@ -266,7 +504,10 @@ func loadMiseSource(
 			VersionArg: versionArg,
 			Optional:   false,
 			Source:     "mise:" + path,
+			File:       path,
+			Position:   positions.lookup("tools", name),
 		}
+		recordConstraint(tool, version, tool.Source)
 
 		tools[name] = tool
 	}
@@ -289,6 +530,32 @@ func extractMiseVersion(value any) string {
 	return ""
 }
 
+// ReadVersionFile returns the version a mise.toml or .tool-versions file selects for
+// the named tool, or "" if the file doesn't exist or selects none.
+func ReadVersionFile(path, name string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	if filepath.Ext(path) == ".toml" {
+		var miseCfg struct {
+			Tools map[string]any `toml:"tools"`
+		}
+		if err := toml.Unmarshal(data, &miseCfg); err != nil {
+			return ""
+		}
+		return extractMiseVersion(miseCfg.Tools[name])
+	}
+
+	for line := range strings.Lines(string(data)) {
+		if parts := strings.Fields(line); len(parts) >= 2 && parts[0] == name {
+			return parts[1]
+		}
+	}
+	return ""
+}
+
 // loadToolVersionsSource loads tools from a .tool-versions file.
 func loadToolVersionsSource(
 	path string,
@@ -307,7 +574,9 @@ func loadToolVersionsSource(
 	}()
 
 	scanner := bufio.NewScanner(file)
+	lineNumber := 0
 	for scanner.Scan() {
+		lineNumber++
 		line := strings.TrimSpace(scanner.Text())
 
 		// Skip empty lines and comments
@@ -324,8 +593,9 @@ func loadToolVersionsSource(
 		name := parts[0]
 		version := parts[1]
 
-		// Don't override existing tools from main config
-		if _, exists := tools[name]; exists {
+		// Don't override existing tools from main config, but note their constraints for merging
+		if existing, exists := tools[name]; exists {
+			recordConstraint(existing, version, "tool-versions:"+path)
 			continue
 		}
 
@@ -356,7 +626,10 @@ func loadToolVersionsSource(
 			VersionArg: versionArg,
 			Optional:   false,
 			Source:     "tool-versions:" + path,
+			File:       path,
+			Position:   Position{Line: lineNumber, Col: 1},
 		}
+		recordConstraint(tool, version, tool.Source)
 
 		tools[name] = tool
 	}
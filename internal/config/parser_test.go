@@ -1,8 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -96,6 +99,270 @@ version = ">=1.20.0"
 		}
 	})
 
+	t.Run("loads when conditions", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		content := `
+[hadolint]
+cli = "hadolint"
+when = { file_exists = "Dockerfile" }
+
+[codecov]
+cli = "codecov"
+when = { env = "CI", equals = "true" }
+`
+		if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := Load(configPath)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+
+		hadolint := cfg.Tools["hadolint"]
+		if hadolint.When == nil || hadolint.When.FileExists != "Dockerfile" {
+			t.Errorf("expected file_exists condition, got %+v", hadolint.When)
+		}
+
+		codecov := cfg.Tools["codecov"]
+		if codecov.When == nil || codecov.When.Env != "CI" || codecov.When.Equals != "true" {
+			t.Errorf("expected env condition, got %+v", codecov.When)
+		}
+	})
+
+	t.Run("loads alternatives", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		content := `
+[docker]
+version = ">=20.0.0"
+alternatives = [
+  "docker",
+  { cli = "podman", version = ">=4.0.0" },
+]
+`
+		if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := Load(configPath)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+
+		alternatives := cfg.Tools["docker"].Alternatives
+		if len(alternatives) != 2 {
+			t.Fatalf("expected 2 alternatives, got %d", len(alternatives))
+		}
+		if alternatives[0].CLI != "docker" || alternatives[0].Version != "" {
+			t.Errorf("unexpected string alternative: %+v", alternatives[0])
+		}
+		if alternatives[1].CLI != "podman" || alternatives[1].Version != ">=4.0.0" {
+			t.Errorf("unexpected table alternative: %+v", alternatives[1])
+		}
+	})
+
+	t.Run("returns error for invalid alternative", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		content := `
+[docker]
+alternatives = [{ cli = 4 }]
+`
+		if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Load(configPath); err == nil {
+			t.Error("expected error for a non-string alternative key")
+		}
+	})
+
+	t.Run("records unknown alternative keys", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		content := `[docker]
+alternatives = [{ cli = "podman", verison = ">=4.0.0" }]
+`
+		if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := Load(configPath)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cli := cfg.Tools["docker"].Alternatives[0].CLI; cli != "podman" {
+			t.Errorf("expected the alternative to load, got %q", cli)
+		}
+		expected := `unknown key "docker.alternatives.verison" (did you mean "version"?)`
+		if len(cfg.Unknown) != 1 || cfg.Unknown[0].Message != expected {
+			t.Fatalf("expected %q, got %v", expected, cfg.Unknown)
+		}
+		if pos := cfg.Unknown[0].Position; pos != (Position{Line: 2, Col: 35}) {
+			t.Errorf("unexpected position %+v", pos)
+		}
+	})
+
+	t.Run("loads custom checks", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		content := `
+[docker]
+cli = "docker"
+
+[[docker.checks]]
+name = "daemon running"
+command = "docker info"
+timeout = "10s"
+
+[[docker.checks]]
+command = "docker context show"
+stdout_pattern = "^default$"
+exit_code = 0
+`
+		if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := Load(configPath)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+
+		checks := cfg.Tools["docker"].Checks
+		if len(checks) != 2 {
+			t.Fatalf("expected 2 checks, got %d", len(checks))
+		}
+		if checks[0].Name != "daemon running" || checks[0].Timeout != "10s" {
+			t.Errorf("unexpected first check: %+v", checks[0])
+		}
+		if checks[1].StdoutPattern != "^default$" {
+			t.Errorf("unexpected second check: %+v", checks[1])
+		}
+	})
+
+	t.Run("loads env sections", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		content := `
+[go]
+cli = "go"
+
+[env.GOPRIVATE]
+pattern = "github.com/drape-io"
+
+[env.JAVA_HOME]
+name = "Java home"
+is_dir = true
+optional = true
+message = "Set JAVA_HOME to your JDK"
+`
+		if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := Load(configPath)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+
+		if len(cfg.Tools) != 1 {
+			t.Errorf("expected env sections not to be parsed as tools, got %d tools", len(cfg.Tools))
+		}
+		if len(cfg.Env) != 2 {
+			t.Fatalf("expected 2 env requirements, got %d", len(cfg.Env))
+		}
+		if cfg.Env["GOPRIVATE"].Pattern != "github.com/drape-io" {
+			t.Errorf("unexpected GOPRIVATE config: %+v", cfg.Env["GOPRIVATE"])
+		}
+
+		env := configToEnv("JAVA_HOME", cfg.Env["JAVA_HOME"])
+		if env.Name != "Java home" || env.Variable != "JAVA_HOME" || !env.IsDir || !env.Optional {
+			t.Errorf("unexpected JAVA_HOME requirement: %+v", env)
+		}
+	})
+
+	t.Run("keeps declaration order and positions", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		writeTestFile(t, configPath, `
+go.cli = "go"
+
+[zig]
+cli = "zig"
+
+[env.HOME]
+is_dir = true
+
+[apt]
+cli = "apt"
+`)
+
+		cfg, err := Load(configPath)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+
+		if !slices.Equal(cfg.Order, []string{"go", "zig", "apt"}) {
+			t.Errorf("expected declaration order, got %v", cfg.Order)
+		}
+		if pos := cfg.Position("apt"); pos != (Position{Line: 10, Col: 1}) {
+			t.Errorf("unexpected position for apt: %+v", pos)
+		}
+		if pos := cfg.Position("go"); pos.Line != 2 {
+			t.Errorf("expected dotted key tool on line 2, got %+v", pos)
+		}
+	})
+
+	t.Run("reports unknown keys without failing", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		writeTestFile(t, configPath, `
+[go]
+cli = "go"
+verison = ">=1.20"
+`)
+
+		cfg, err := Load(configPath)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+
+		if cfg.Tools["go"].Version != "" {
+			t.Errorf("expected misspelled key to be ignored, got %+v", cfg.Tools["go"])
+		}
+		if len(cfg.Unknown) != 1 || cfg.Unknown[0].Line != 4 ||
+			!strings.Contains(cfg.Unknown[0].Message, `did you mean "version"`) {
+			t.Errorf("expected unknown key on line 4, got %v", cfg.Unknown)
+		}
+	})
+
+	t.Run("returns error for wrong value type", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		writeTestFile(t, configPath, `
+[node]
+cli = "node"
+version = 18
+`)
+
+		_, err := Load(configPath)
+		if err == nil || !strings.Contains(err.Error(), ".chex.toml:4:1") {
+			t.Errorf("expected error pointing at line 4, got %v", err)
+		}
+	})
+
 	t.Run("returns error for non-existent file", func(t *testing.T) {
 		_, err := Load("/nonexistent/path/.chex.toml")
 		if err == nil {
@@ -157,6 +424,23 @@ func TestConfigToTool(t *testing.T) {
 			t.Errorf("expected name 'Custom Name', got %q", tool.Name)
 		}
 	})
+
+	t.Run("merges subcommands and plugins", func(t *testing.T) {
+		cfg := ToolConfig{
+			CLI:         "docker",
+			Subcommands: []Subcommand{{Name: "compose", Version: ">=2.20"}},
+			Plugins:     []Subcommand{{Name: "buildx"}},
+		}
+
+		tool := configToTool("docker", cfg, "config")
+
+		if len(tool.Subcommands) != 2 {
+			t.Fatalf("expected 2 subcommands, got %d", len(tool.Subcommands))
+		}
+		if tool.Subcommands[0].Name != "compose" || tool.Subcommands[1].Name != "buildx" {
+			t.Errorf("unexpected subcommands: %+v", tool.Subcommands)
+		}
+	})
 }
 
 func TestExtractMiseVersion(t *testing.T) {
@@ -214,6 +498,48 @@ func loadAndMergeHelper(t *testing.T, configPath, baseDir string) *LoadResult {
 	return result
 }
 
+func TestLoadAndMergeOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".chex.toml")
+
+	writeTestFile(t, configPath, `
+[terraform]
+cli = "terraform"
+
+[go]
+cli = "go"
+veresion = "1.22"
+`)
+	writeTestFile(t, filepath.Join(tmpDir, "mise.toml"), `
+[tools]
+python = "3.12"
+node = "20"
+`)
+	writeTestFile(t, filepath.Join(tmpDir, ".tool-versions"), "ruby 3.3.0\ngolang 1.22.0\n")
+
+	result := loadAndMergeHelper(t, configPath, tmpDir)
+
+	expected := []string{"terraform", "go", "python", "node", "ruby", "golang"}
+	if !slices.Equal(result.Order, expected) {
+		t.Errorf("expected order %v, got %v", expected, result.Order)
+	}
+
+	locations := map[string]string{
+		"go":     configPath + ":5:1",
+		"node":   filepath.Join(tmpDir, "mise.toml") + ":4:1",
+		"golang": filepath.Join(tmpDir, ".tool-versions") + ":2:1",
+	}
+	for name, location := range locations {
+		if got := result.Tools[name].Location(); got != location {
+			t.Errorf("expected %s defined at %s, got %s", name, location, got)
+		}
+	}
+
+	if len(result.Warnings) == 0 || !strings.Contains(result.Warnings[0], configPath+":7:1") {
+		t.Errorf("expected unknown key warning, got %v", result.Warnings)
+	}
+}
+
 func TestLoadAndMerge(t *testing.T) {
 	t.Run("loads main config only", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -236,6 +562,178 @@ version = ">=1.20.0"
 		}
 	})
 
+	t.Run("resolves when file_exists relative to root", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		writeTestFile(t, configPath, `
+[terraform]
+cli = "terraform"
+when = { file_exists = "infra" }
+`)
+
+		result := loadAndMergeHelper(t, configPath, tmpDir)
+
+		when := result.Tools["terraform"].When
+		if when == nil || when.FileExists != filepath.Join(tmpDir, "infra") {
+			t.Errorf("expected file_exists resolved to root, got %+v", when)
+		}
+	})
+
+	t.Run("resolves sha256_file relative to root", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		writeTestFile(t, configPath, `
+[kubectl]
+cli = "kubectl"
+sha256_file = "checksums.txt"
+`)
+
+		result := loadAndMergeHelper(t, configPath, tmpDir)
+
+		kubectl := result.Tools["kubectl"]
+		if kubectl.SHA256File != filepath.Join(tmpDir, "checksums.txt") {
+			t.Errorf("expected sha256_file resolved to root, got %q", kubectl.SHA256File)
+		}
+	})
+
+	t.Run("loads version normalization", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		writeTestFile(t, configPath, `
+[java]
+cli = "java"
+version = ">=17"
+version_preset = "java"
+version_replace = [{ from = "-ea$", to = "" }]
+version_coerce = true
+`)
+
+		result := loadAndMergeHelper(t, configPath, tmpDir)
+
+		java := result.Tools["java"]
+		if java.VersionPreset != "java" || !java.VersionCoerce {
+			t.Errorf("expected preset and coercion, got %+v", java)
+		}
+		if len(java.VersionReplace) != 1 || java.VersionReplace[0].From != "-ea$" {
+			t.Errorf("expected one replacement, got %+v", java.VersionReplace)
+		}
+	})
+
+	t.Run("applies global allow_prerelease default", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		writeTestFile(t, configPath, `
+[chex]
+allow_prerelease = true
+
+[node]
+cli = "node"
+version = ">=20"
+
+[go]
+cli = "go"
+version = ">=1.20"
+allow_prerelease = false
+`)
+
+		result := loadAndMergeHelper(t, configPath, tmpDir)
+
+		if allow := result.Tools["node"].AllowPrerelease; allow == nil || !*allow {
+			t.Errorf("expected node to inherit allow_prerelease, got %v", allow)
+		}
+		if allow := result.Tools["go"].AllowPrerelease; allow == nil || *allow {
+			t.Errorf("expected go to override allow_prerelease, got %v", allow)
+		}
+	})
+
+	t.Run("loads file requirements", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		writeTestFile(t, configPath, `
+[files.kubeconfig]
+path = "~/.kube/config"
+mode = "0600"
+
+[files.dotenv]
+name = ".env"
+path = ".env"
+newer_than = ".env.example"
+message = "Run: cp .env.example .env"
+
+[files.legacy]
+path = "Makefile.old"
+exists = false
+`)
+
+		result := loadAndMergeHelper(t, configPath, tmpDir)
+
+		if len(result.Files) != 3 {
+			t.Fatalf("expected 3 file requirements, got %d", len(result.Files))
+		}
+
+		home, err := os.UserHomeDir()
+		if err != nil {
+			t.Fatal(err)
+		}
+		kubeconfig := result.Files["kubeconfig"]
+		if kubeconfig.Path != filepath.Join(home, ".kube", "config") || !kubeconfig.Exists {
+			t.Errorf("unexpected kubeconfig requirement: %+v", kubeconfig)
+		}
+
+		dotenv := result.Files["dotenv"]
+		if dotenv.Name != ".env" || dotenv.Path != filepath.Join(tmpDir, ".env") {
+			t.Errorf("unexpected dotenv requirement: %+v", dotenv)
+		}
+		if dotenv.NewerThan != filepath.Join(tmpDir, ".env.example") {
+			t.Errorf("expected newer_than resolved to root, got %q", dotenv.NewerThan)
+		}
+
+		if result.Files["legacy"].Exists {
+			t.Error("expected legacy file to be required absent")
+		}
+	})
+
+	t.Run("loads service requirements", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, ".chex.toml")
+
+		writeTestFile(t, configPath, `
+[services.docker]
+socket = "/var/run/docker.sock"
+
+[services.postgres]
+address = "localhost:5432"
+timeout = "500ms"
+
+[services.api]
+address = "localhost:8080"
+http_path = "/healthz"
+http_status = 204
+optional = true
+`)
+
+		result := loadAndMergeHelper(t, configPath, tmpDir)
+
+		if len(result.Services) != 3 {
+			t.Fatalf("expected 3 service requirements, got %d", len(result.Services))
+		}
+		if result.Services["docker"].Socket != "/var/run/docker.sock" {
+			t.Errorf("unexpected docker service: %+v", result.Services["docker"])
+		}
+		if result.Services["postgres"].Timeout != "500ms" {
+			t.Errorf("unexpected postgres service: %+v", result.Services["postgres"])
+		}
+		api := result.Services["api"]
+		if api.HTTPPath != "/healthz" || api.HTTPStatus != 204 || !api.Optional {
+			t.Errorf("unexpected api service: %+v", api)
+		}
+	})
+
 	t.Run("auto-detects mise.toml", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		configPath := filepath.Join(tmpDir, ".chex.toml")
@@ -498,7 +996,7 @@ version = ">=20.0.0"
 		}
 
 		tools := make(map[string]*Tool)
-		warnings := loadSource(sourcePath, "chex", tools, false, false, false)
+		warnings := loadSource(sourcePath, "chex", tmpDir, tools, false, false, false)
 
 		if len(warnings) != 0 {
 			t.Errorf("expected no warnings for chex source, got %d", len(warnings))
@@ -510,7 +1008,7 @@ version = ">=20.0.0"
 
 	t.Run("returns error for unknown source type", func(t *testing.T) {
 		tools := make(map[string]*Tool)
-		warnings := loadSource("/some/path", "unknown-type", tools, false, false, false)
+		warnings := loadSource("/some/path", "unknown-type", "", tools, false, false, false)
 
 		if len(warnings) == 0 {
 			t.Error("expected warning for unknown source type")
@@ -520,3 +1018,236 @@ version = ">=20.0.0"
 		}
 	})
 }
+
+func TestLoadFormats(t *testing.T) {
+	configs := map[string]string{
+		".chex.toml": `
+[chex]
+merge_strategy = "strictest"
+
+[node]
+name = "Node.js"
+cli = "node"
+version = ">=20"
+alternatives = ["nodejs", { cli = "bun", version = ">=1.0" }]
+
+[[node.checks]]
+command = "npm --version"
+exit_code = 0
+
+[go]
+cli = "go"
+optional = true
+when = { env = "CI" }
+
+[env.GOPATH]
+is_dir = true
+`,
+		".chex.yaml": `
+chex:
+  merge_strategy: strictest
+
+node:
+  name: Node.js
+  cli: node
+  version: ">=20"
+  alternatives:
+    - nodejs
+    - cli: bun
+      version: ">=1.0"
+  checks:
+    - command: npm --version
+      exit_code: 0
+
+go:
+  cli: go
+  optional: true
+  when: {env: CI}
+
+env:
+  GOPATH:
+    is_dir: true
+`,
+		".chex.json": `{
+  "chex": {"merge_strategy": "strictest"},
+  "node": {
+    "name": "Node.js",
+    "cli": "node",
+    "version": ">=20",
+    "alternatives": ["nodejs", {"cli": "bun", "version": ">=1.0"}],
+    "checks": [{"command": "npm --version", "exit_code": 0}]
+  },
+  "go": {"cli": "go", "optional": true, "when": {"env": "CI"}},
+  "env": {"GOPATH": {"is_dir": true}}
+}
+`,
+	}
+
+	loaded := make(map[string]*Config)
+	for name, content := range configs {
+		configPath := filepath.Join(t.TempDir(), name)
+		writeTestFile(t, configPath, content)
+
+		cfg, err := Load(configPath)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", name, err)
+		}
+		if len(cfg.Unknown) > 0 {
+			t.Errorf("%s: unexpected unknown keys %v", name, cfg.Unknown)
+		}
+		loaded[name] = cfg
+	}
+
+	expected := loaded[".chex.toml"]
+	for _, name := range []string{".chex.yaml", ".chex.json"} {
+		cfg := loaded[name]
+		if !slices.Equal(cfg.Order, []string{"node", "go"}) {
+			t.Errorf("%s: expected declaration order, got %v", name, cfg.Order)
+		}
+		if !reflect.DeepEqual(cfg.Tools, expected.Tools) {
+			t.Errorf("%s: tools differ from TOML:\n%+v\n%+v", name, cfg.Tools, expected.Tools)
+		}
+		if !reflect.DeepEqual(cfg.Env, expected.Env) || !reflect.DeepEqual(cfg.Chex, expected.Chex) {
+			t.Errorf("%s: settings differ from TOML: %+v %+v", name, cfg.Env, cfg.Chex)
+		}
+	}
+
+	if pos := loaded[".chex.yaml"].Position("go", "optional"); pos != (Position{Line: 19, Col: 3}) {
+		t.Errorf("unexpected YAML position for go.optional: %+v", pos)
+	}
+	if pos := loaded[".chex.json"].Position("go"); pos != (Position{Line: 10, Col: 3}) {
+		t.Errorf("unexpected JSON position for go: %+v", pos)
+	}
+
+	t.Run("reports YAML problems at their source", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), ".chex.yml")
+		writeTestFile(t, configPath, "go:\n  cli: go\n  version: 1.20\n")
+
+		_, err := Load(configPath)
+		if err == nil || !strings.Contains(err.Error(), ".chex.yml:3:3: error: go.version: expected string, got number") {
+			t.Errorf("expected type error on line 3, got %v", err)
+		}
+	})
+
+	t.Run("reports syntax errors", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), ".chex.json")
+		writeTestFile(t, configPath, "{\n  \"go\": {\"cli\": \"go\",}\n")
+
+		if _, err := Load(configPath); err == nil {
+			t.Error("expected error for invalid JSON")
+		}
+	})
+}
+
+func TestConfigDiscovery(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []string
+		expected string
+	}{
+		{name: "toml first", files: []string{".chex.json", ".chex.yaml", ".chex.toml"}, expected: "toml"},
+		{name: "yaml before json", files: []string{".chex.json", ".chex.yaml"}, expected: "yaml"},
+		{name: "yml", files: []string{".chex.yml"}, expected: "yml"},
+		{name: "json", files: []string{".chex.json"}, expected: "json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for _, file := range tt.files {
+				format := strings.TrimPrefix(filepath.Ext(file), ".")
+				content := fmt.Sprintf("[%s]\ncli = %q\n", format, format)
+				if format != "toml" {
+					content = fmt.Sprintf(`{%q: {"cli": %q}}`, format, format)
+				}
+				writeTestFile(t, filepath.Join(tmpDir, file), content)
+			}
+
+			result := loadAndMergeHelper(t, "", tmpDir)
+			if !slices.Equal(result.Order, []string{tt.expected}) {
+				t.Errorf("expected config from .chex.%s, got tools %v", tt.expected, result.Order)
+			}
+		})
+	}
+}
+
+func TestExistingConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	if name := ExistingConfig(tmpDir); name != "" {
+		t.Errorf("expected no config in an empty directory, got %q", name)
+	}
+
+	writeTestFile(t, filepath.Join(tmpDir, ".chex.yml"), "{}")
+	if name := ExistingConfig(tmpDir); name != ".chex.yml" {
+		t.Errorf("expected .chex.yml, got %q", name)
+	}
+}
+
+func TestReadVersionFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	toolVersions := filepath.Join(tmpDir, ".tool-versions")
+	writeTestFile(t, toolVersions, "# nodejs 16\nnodejs 18.19.0 20.11.0\npython 3.12.1\n")
+	miseToml := filepath.Join(tmpDir, "mise.toml")
+	writeTestFile(t, miseToml, "[tools]\nnode = \"20\"\nterraform = { version = \"1.6.0\" }\n")
+
+	tests := []struct {
+		path     string
+		name     string
+		expected string
+	}{
+		{path: toolVersions, name: "nodejs", expected: "18.19.0"},
+		{path: toolVersions, name: "ruby", expected: ""},
+		{path: miseToml, name: "node", expected: "20"},
+		{path: miseToml, name: "terraform", expected: "1.6.0"},
+		{path: filepath.Join(tmpDir, "missing"), name: "node", expected: ""},
+	}
+
+	for _, tt := range tests {
+		if got := ReadVersionFile(tt.path, tt.name); got != tt.expected {
+			t.Errorf("ReadVersionFile(%s, %s) = %q, expected %q", filepath.Base(tt.path), tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestLoadFormatsAgree(t *testing.T) {
+	// Values the decoder accepts but validate rejects load the same way in every format
+	configs := map[string]string{
+		".chex.toml": "[go]\ncli = \"go\"\nversion_scheme = \"bogus\"\n\n" +
+			"[[go.checks]]\nname = \"env\"\n\n[node]\ncli = \"node\"\n",
+		".chex.yaml": "go:\n  cli: go\n  version_scheme: bogus\n  checks:\n    - name: env\nnode:\n  cli: node\n",
+		".chex.json": `{"go": {"cli": "go", "version_scheme": "bogus", "checks": [{"name": "env"}]},` +
+			` "node": {"cli": "node"}}`,
+	}
+
+	for name, content := range configs {
+		t.Run(name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), name)
+			writeTestFile(t, configPath, content)
+
+			result, err := LoadAndMerge(configPath, "")
+			if err != nil {
+				t.Fatalf("LoadAndMerge() error = %v", err)
+			}
+			if !slices.Equal(result.Order, []string{"go", "node"}) {
+				t.Errorf("expected both tools, got %v", result.Order)
+			}
+			if scheme := result.Tools["go"].VersionScheme; scheme != "bogus" {
+				t.Errorf("expected the scheme to be left to the checker, got %q", scheme)
+			}
+
+			diagnostics, err := Validate(configPath, "")
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			var messages []string
+			for _, d := range diagnostics {
+				messages = append(messages, d.Message)
+			}
+			for _, expected := range []string{`unknown version_scheme "bogus"`, `missing required key "command"`} {
+				if !slices.ContainsFunc(messages, func(m string) bool { return strings.Contains(m, expected) }) {
+					t.Errorf("expected validate to report %q, got %q", expected, messages)
+				}
+			}
+		})
+	}
+}
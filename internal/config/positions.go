@@ -0,0 +1,296 @@
+package config
+
+import (
+	"strings"
+)
+
+// Position is a 1-based line and column in a configuration file.
+type Position struct {
+	Line int
+	Col  int
+}
+
+// compare orders positions by line, then column.
+func (p Position) compare(other Position) int {
+	if p.Line != other.Line {
+		return p.Line - other.Line
+	}
+	return p.Col - other.Col
+}
+
+// keyPositions maps dotted key paths ("go.version", "go.when.env") to where each key
+// is first written in TOML source. Keys inside inline tables and arrays of tables
+// are recorded without array indices.
+type keyPositions map[string]Position
+
+// inlineContext is an inline table or array being scanned, with the key path its
+// keys belong to.
+type inlineContext struct {
+	prefix  string
+	isTable bool
+}
+
+// scanKeyPositions records the position of every table header and key in data.
+// It understands enough TOML to skip strings and comments; the data is expected to
+// have parsed successfully already.
+func scanKeyPositions(data string) keyPositions {
+	positions := keyPositions{}
+	s := &positionScanner{data: data, line: 1, col: 1, positions: positions}
+	s.scan()
+	return positions
+}
+
+// lookup returns the position of the longest recorded prefix of path, so keys the
+// scanner couldn't place fall back to their table.
+func (p keyPositions) lookup(path ...string) Position {
+	for n := len(path); n > 0; n-- {
+		if pos, ok := p[strings.Join(path[:n], ".")]; ok {
+			return pos
+		}
+	}
+	return Position{Line: 1, Col: 1}
+}
+
+// positionScanner walks TOML source one character at a time, tracking line and column.
+type positionScanner struct {
+	data      string
+	i         int
+	line, col int
+	table     string
+	stack     []inlineContext
+	positions keyPositions
+}
+
+func (s *positionScanner) done() bool { return s.i >= len(s.data) }
+
+func (s *positionScanner) peek() byte { return s.data[s.i] }
+
+func (s *positionScanner) advance() {
+	if s.data[s.i] == '\n' {
+		s.line++
+		s.col = 1
+	} else {
+		s.col++
+	}
+	s.i++
+}
+
+// record notes where path is first written. Dotted keys and headers also define
+// their parent tables, so those are recorded at the same position.
+func (s *positionScanner) record(path string, pos Position) {
+	for i := range len(path) + 1 {
+		if i < len(path) && path[i] != '.' {
+			continue
+		}
+		if _, ok := s.positions[path[:i]]; !ok {
+			s.positions[path[:i]] = pos
+		}
+	}
+}
+
+func (s *positionScanner) skipSpaces() {
+	for !s.done() && (s.peek() == ' ' || s.peek() == '\t') {
+		s.advance()
+	}
+}
+
+func (s *positionScanner) skipLine() {
+	for !s.done() && s.peek() != '\n' {
+		s.advance()
+	}
+}
+
+// scan handles top-level lines: blank lines, comments, table headers and key/value pairs.
+func (s *positionScanner) scan() {
+	for !s.done() {
+		s.skipSpaces()
+		if s.done() {
+			return
+		}
+
+		switch s.peek() {
+		case '\n', '\r':
+			s.advance()
+		case '#':
+			s.skipLine()
+		case '[':
+			s.scanHeader()
+		default:
+			path := s.scanKey(s.table)
+			s.scanValue(path)
+		}
+	}
+}
+
+// scanHeader reads a [table] or [[array-of-tables]] header.
+func (s *positionScanner) scanHeader() {
+	pos := Position{Line: s.line, Col: s.col}
+	for !s.done() && s.peek() == '[' {
+		s.advance()
+	}
+
+	start := s.i
+	for !s.done() && s.peek() != ']' && s.peek() != '\n' {
+		s.skipQuoted()
+		if !s.done() && s.peek() != ']' && s.peek() != '\n' {
+			s.advance()
+		}
+	}
+
+	s.table = normalizeKey(s.data[start:s.i])
+	s.record(s.table, pos)
+	s.skipLine()
+}
+
+// scanKey reads a (possibly dotted or quoted) key up to '=' and records it under prefix.
+func (s *positionScanner) scanKey(prefix string) string {
+	pos := Position{Line: s.line, Col: s.col}
+	start := s.i
+	for !s.done() && s.peek() != '=' && s.peek() != '\n' {
+		s.skipQuoted()
+		if !s.done() && s.peek() != '=' && s.peek() != '\n' {
+			s.advance()
+		}
+	}
+
+	path := joinKey(prefix, normalizeKey(s.data[start:s.i]))
+	s.record(path, pos)
+	if !s.done() && s.peek() == '=' {
+		s.advance()
+	}
+	return path
+}
+
+// scanValue skips a value, recording keys inside any inline tables it contains.
+// It returns at the end of the value's last line.
+func (s *positionScanner) scanValue(path string) {
+	pending := path
+	expectKey := false
+
+	for !s.done() {
+		if expectKey {
+			s.skipBlank()
+			if s.done() {
+				return
+			}
+			if s.peek() != '}' {
+				pending = s.scanKey(s.stack[len(s.stack)-1].prefix)
+				expectKey = false
+				continue
+			}
+		}
+
+		switch c := s.peek(); c {
+		case '"', '\'':
+			s.skipQuoted()
+		case '#':
+			s.skipLine()
+		case '{', '[':
+			prefix := pending
+			if prefix == "" && len(s.stack) > 0 {
+				prefix = s.stack[len(s.stack)-1].prefix
+			}
+			s.stack = append(s.stack, inlineContext{prefix: prefix, isTable: c == '{'})
+			pending = ""
+			expectKey = c == '{'
+			s.advance()
+		case '}', ']':
+			if len(s.stack) > 0 {
+				s.stack = s.stack[:len(s.stack)-1]
+			}
+			s.advance()
+		case ',':
+			pending = ""
+			expectKey = len(s.stack) > 0 && s.stack[len(s.stack)-1].isTable
+			s.advance()
+		case '\n':
+			if len(s.stack) == 0 {
+				return
+			}
+			s.advance()
+		default:
+			s.advance()
+		}
+	}
+}
+
+// skipBlank skips whitespace, newlines and comments inside a multi-line value.
+func (s *positionScanner) skipBlank() {
+	for !s.done() {
+		switch s.peek() {
+		case ' ', '\t', '\r', '\n':
+			s.advance()
+		case '#':
+			s.skipLine()
+		default:
+			return
+		}
+	}
+}
+
+// skipQuoted skips a basic, literal or multi-line string starting at the current
+// character, if there is one.
+func (s *positionScanner) skipQuoted() {
+	if s.done() || (s.peek() != '"' && s.peek() != '\'') {
+		return
+	}
+
+	quote := s.data[s.i : s.i+1]
+	if strings.HasPrefix(s.data[s.i:], strings.Repeat(quote, 3)) {
+		quote = strings.Repeat(quote, 3)
+	}
+	for range quote {
+		s.advance()
+	}
+
+	for !s.done() {
+		if quote[0] == '"' && s.peek() == '\\' {
+			s.advance()
+			if !s.done() {
+				s.advance()
+			}
+			continue
+		}
+		if strings.HasPrefix(s.data[s.i:], quote) {
+			for range quote {
+				s.advance()
+			}
+			return
+		}
+		s.advance()
+	}
+}
+
+// normalizeKey turns a written key such as ` "a b" . c ` into its dotted path "a b.c".
+func normalizeKey(key string) string {
+	var parts []string
+	var current strings.Builder
+	var quote byte
+
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteByte(c)
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '.':
+			parts = append(parts, strings.TrimSpace(current.String()))
+			current.Reset()
+		case c != ' ' && c != '\t':
+			current.WriteByte(c)
+		}
+	}
+	parts = append(parts, strings.TrimSpace(current.String()))
+	return strings.Join(parts, ".")
+}
+
+// joinKey appends key to a dotted prefix.
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,49 @@
+package config
+
+import "testing"
+
+func TestScanKeyPositions(t *testing.T) {
+	data := `# comment with [brackets] and key = "value"
+[chex]
+sources = [
+  { path = "mise.toml", type = "mise" },
+]
+
+[go]
+cli = "go" # trailing = comment
+version_pattern = '''go(\d+)
+[not.a.table]'''
+when = { env = "CI", equals = "true" }
+checks = [{ command = "go env", exit_code = 0 }]
+
+["quoted key".sub]
+"a.b" = 1
+x.y = 2
+`
+
+	positions := scanKeyPositions(data)
+
+	tests := []struct {
+		path     []string
+		expected Position
+	}{
+		{path: []string{"chex"}, expected: Position{Line: 2, Col: 1}},
+		{path: []string{"chex", "sources"}, expected: Position{Line: 3, Col: 1}},
+		{path: []string{"chex", "sources", "path"}, expected: Position{Line: 4, Col: 5}},
+		{path: []string{"chex", "sources", "type"}, expected: Position{Line: 4, Col: 25}},
+		{path: []string{"go", "cli"}, expected: Position{Line: 8, Col: 1}},
+		{path: []string{"go", "version_pattern"}, expected: Position{Line: 9, Col: 1}},
+		{path: []string{"go", "when", "equals"}, expected: Position{Line: 11, Col: 22}},
+		{path: []string{"go", "checks", "exit_code"}, expected: Position{Line: 12, Col: 33}},
+		{path: []string{"quoted key", "sub", "a.b"}, expected: Position{Line: 15, Col: 1}},
+		{path: []string{"quoted key", "sub", "x", "y"}, expected: Position{Line: 16, Col: 1}},
+		{path: []string{"go", "missing"}, expected: Position{Line: 7, Col: 1}},
+		{path: []string{"not"}, expected: Position{Line: 1, Col: 1}},
+	}
+
+	for _, tt := range tests {
+		if got := positions.lookup(tt.path...); got != tt.expected {
+			t.Errorf("lookup(%q) = %+v, expected %+v", tt.path, got, tt.expected)
+		}
+	}
+}
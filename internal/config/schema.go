@@ -0,0 +1,382 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// JSONSchema is the subset of JSON Schema (draft-07) used to describe and validate
+// chex configuration files.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"` // false or *JSONSchema
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+	AllOf                []*JSONSchema          `json:"allOf,omitempty"`
+	Definitions          map[string]*JSONSchema `json:"definitions,omitempty"`
+}
+
+// Values accepted by settings that take one of a fixed set of names. The checker
+// implements the behavior behind each name using the same constants.
+var (
+	validSourceTypes     = []string{SourceChex, SourceMise, SourceToolVersions}
+	validMergeStrategies = []string{MergeFirstWins, MergeIntersect, MergeStrictest}
+	validVersionSchemes  = []string{SchemeSemver, SchemeCalver, SchemePEP440, SchemeLoose, SchemeDate}
+	validVersionSources  = []string{VersionSourceCommand, VersionSourceBuildInfo}
+	validVersionPresets  = []string{PresetJava, PresetPython2, PresetRuby}
+	validManagers        = []string{ManagerMise, ManagerAsdf, ManagerHomebrew}
+)
+
+// schemaEnums lists the allowed values of settings, keyed by struct and TOML key.
+var schemaEnums = map[string][]string{
+	"ChexConfig.merge_strategy":     validMergeStrategies,
+	"Source.type":                   validSourceTypes,
+	"ToolConfig.version_scheme":     validVersionSchemes,
+	"ToolConfig.version_source":     validVersionSources,
+	"ToolConfig.version_preset":     validVersionPresets,
+	"ToolConfig.must_be_managed_by": validManagers,
+}
+
+// Schema returns the JSON Schema for the config file in any format (.chex.toml,
+// .chex.yaml, .chex.yml or .chex.json), generated from the config structs.
+// Any table other than chex, env, files and services is a tool.
+func Schema() *JSONSchema {
+	return configSchema()
+}
+
+var configSchema = sync.OnceValue(func() *JSONSchema {
+	g := &schemaGenerator{definitions: make(map[string]*JSONSchema)}
+
+	return &JSONSchema{
+		Schema:      "http://json-schema.org/draft-07/schema#",
+		Title:       "chex configuration",
+		Description: "Tables other than chex, env, files and services define tools to check.",
+		Type:        "object",
+		Properties: map[string]*JSONSchema{
+			"chex":     g.typeSchema(reflect.TypeFor[ChexConfig]()),
+			"env":      g.tableSchema(reflect.TypeFor[EnvConfig](), "Environment variables, keyed by name"),
+			"files":    g.tableSchema(reflect.TypeFor[FileConfig](), "Files and directories, keyed by name"),
+			"services": g.tableSchema(reflect.TypeFor[ServiceConfig](), "Local services, keyed by name"),
+		},
+		AdditionalProperties: g.typeSchema(reflect.TypeFor[ToolConfig]()),
+		Definitions:          g.definitions,
+	}
+})
+
+// schemaGenerator builds schemas for Go types, collecting struct definitions.
+type schemaGenerator struct {
+	definitions map[string]*JSONSchema
+}
+
+func (g *schemaGenerator) tableSchema(t reflect.Type, description string) *JSONSchema {
+	return &JSONSchema{
+		Description:          description,
+		Type:                 "object",
+		AdditionalProperties: g.typeSchema(t),
+	}
+}
+
+func (g *schemaGenerator) typeSchema(t reflect.Type) *JSONSchema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.typeSchema(t.Elem())
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Slice:
+		return &JSONSchema{Type: "array", Items: g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		return &JSONSchema{}
+	}
+}
+
+// structSchema defines a struct by its TOML keys and returns a reference to it. Keys
+// tagged `jsonschema:"required"` must be present, and each key is described by its
+// field's `desc` tag.
+func (g *schemaGenerator) structSchema(t reflect.Type) *JSONSchema {
+	ref := &JSONSchema{Ref: "#/definitions/" + t.Name()}
+
+	if _, ok := g.definitions[t.Name()]; !ok {
+		def := &JSONSchema{
+			Type:                 "object",
+			Properties:           make(map[string]*JSONSchema),
+			AdditionalProperties: false,
+		}
+		g.definitions[t.Name()] = def
+
+		for i := range t.NumField() {
+			field := t.Field(i)
+			key := field.Tag.Get("toml")
+			if key == "" || key == "-" {
+				continue
+			}
+
+			property := g.typeSchema(field.Type)
+			if slices.Contains(strings.Split(field.Tag.Get("jsonschema"), ","), "required") {
+				def.Required = append(def.Required, key)
+			}
+			property.Enum = schemaEnums[t.Name()+"."+key]
+			def.Properties[key] = describe(property, field.Tag.Get("desc"))
+		}
+	}
+
+	// Types with their own unmarshaler also accept a plain string
+	if reflect.PointerTo(t).Implements(reflect.TypeFor[toml.Unmarshaler]()) {
+		return &JSONSchema{AnyOf: []*JSONSchema{{Type: "string"}, ref}}
+	}
+	return ref
+}
+
+// describe sets a property's description. Draft-07 ignores keywords next to $ref, so
+// a described reference is wrapped in allOf.
+func describe(property *JSONSchema, description string) *JSONSchema {
+	if property.Ref != "" {
+		return &JSONSchema{Description: description, AllOf: []*JSONSchema{property}}
+	}
+	property.Description = description
+	return property
+}
+
+// resolve follows a schema's $ref, or a lone allOf around one, to its definition.
+func (s *JSONSchema) resolve(root *JSONSchema) *JSONSchema {
+	if len(s.AllOf) == 1 {
+		return s.AllOf[0].resolve(root)
+	}
+	if name, ok := strings.CutPrefix(s.Ref, "#/definitions/"); ok {
+		if def, exists := root.Definitions[name]; exists {
+			return def
+		}
+	}
+	return s
+}
+
+// additional returns the schema for keys not listed in Properties, or nil if
+// other keys aren't allowed.
+func (s *JSONSchema) additional() *JSONSchema {
+	if additional, ok := s.AdditionalProperties.(*JSONSchema); ok {
+		return additional
+	}
+	return nil
+}
+
+// lookup returns the schema for the value at path, ignoring array indices, or nil
+// if the schema doesn't allow the path.
+func (s *JSONSchema) lookup(path []string) *JSONSchema {
+	root := s
+	for _, key := range path {
+		s = s.object(root)
+		if s == nil {
+			return nil
+		}
+		s = s.property(key)
+		if s == nil {
+			return nil
+		}
+	}
+	return s.object(root)
+}
+
+// property returns the schema for key in an object described by s, or nil if the
+// key isn't allowed.
+func (s *JSONSchema) property(key string) *JSONSchema {
+	if property, ok := s.Properties[key]; ok {
+		return property
+	}
+	return s.additional()
+}
+
+// object returns the object schema a value at s describes, looking through
+// references, arrays and string shorthands.
+func (s *JSONSchema) object(root *JSONSchema) *JSONSchema {
+	for s != nil {
+		s = s.resolve(root)
+		switch {
+		case s.Items != nil:
+			s = s.Items
+		case len(s.AnyOf) > 0:
+			next := s.AnyOf[len(s.AnyOf)-1]
+			for _, option := range s.AnyOf {
+				if option.resolve(root).Type == "object" {
+					next = option
+				}
+			}
+			s = next
+		default:
+			return s
+		}
+	}
+	return nil
+}
+
+// suggestKey returns the known key closest to the last part of key, or "" if none
+// is close enough to be a likely typo.
+func suggestKey(key []string) string {
+	if len(key) < 2 {
+		return ""
+	}
+	parent := Schema().lookup(key[:len(key)-1])
+	if parent == nil {
+		return ""
+	}
+	return closestKey(key[len(key)-1], slices.Sorted(maps.Keys(parent.Properties)))
+}
+
+// closestKey returns the candidate nearest to typo, if it's close enough to be a typo.
+func closestKey(typo string, candidates []string) string {
+	best, bestDistance := "", max(2, len(typo)/3)+1
+	for _, candidate := range candidates {
+		if d := editDistance(typo, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
+
+// schemaValidator checks decoded configuration values against a schema.
+type schemaValidator struct {
+	root   *JSONSchema
+	report func(path []string, format string, args ...any)
+
+	// reportUnknown, if set, receives unknown keys instead of report
+	reportUnknown func(path []string, format string, args ...any)
+
+	// structural, if set, skips enum and required-key checks, leaving only what the
+	// TOML decoder itself rejects: values of the wrong type
+	structural bool
+}
+
+// validate reports every way value, found at path, doesn't match s.
+func (sv *schemaValidator) validate(s *JSONSchema, value any, path []string) {
+	s = s.resolve(sv.root)
+
+	if len(s.AnyOf) > 0 {
+		var types []string
+		for _, option := range s.AnyOf {
+			option = option.resolve(sv.root)
+			if typeMatches(option.Type, value) {
+				sv.validate(option, value, path)
+				return
+			}
+			types = append(types, option.Type)
+		}
+		sv.report(path, "%s: expected %s, got %s", strings.Join(path, "."), strings.Join(types, " or "), valueType(value))
+		return
+	}
+
+	if s.Type != "" && !typeMatches(s.Type, value) {
+		sv.report(path, "%s: expected %s, got %s", strings.Join(path, "."), s.Type, valueType(value))
+		return
+	}
+
+	if str, ok := value.(string); ok && !sv.structural && len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+		sv.report(path, "unknown %s %q (expected %s)", path[len(path)-1], str, strings.Join(s.Enum, ", "))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		sv.validateObject(s, v, path)
+	case []map[string]any:
+		for _, item := range v {
+			sv.validate(s.Items, item, path)
+		}
+	case []any:
+		for _, item := range v {
+			sv.validate(s.Items, item, path)
+		}
+	}
+}
+
+func (sv *schemaValidator) validateObject(s *JSONSchema, object map[string]any, path []string) {
+	for _, key := range s.Required {
+		if _, ok := object[key]; !ok && !sv.structural {
+			sv.report(path, "%s: missing required key %q", strings.Join(path, "."), key)
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(object)) {
+		keyPath := append(slices.Clone(path), key)
+		if property := s.property(key); property != nil {
+			sv.validate(property, object[key], keyPath)
+			continue
+		}
+
+		report := sv.report
+		if sv.reportUnknown != nil {
+			report = sv.reportUnknown
+		}
+		message := fmt.Sprintf("unknown key %q", strings.Join(keyPath, "."))
+		if suggestion := closestKey(key, slices.Sorted(maps.Keys(s.Properties))); suggestion != "" {
+			message += fmt.Sprintf(" (did you mean %q?)", suggestion)
+		}
+		report(keyPath, "%s", message)
+	}
+}
+
+// valueType names the JSON Schema type of a decoded TOML, YAML or JSON value.
+func valueType(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int64:
+		return "integer"
+	case float64:
+		return "number"
+	case map[string]any:
+		return "object"
+	case []any, []map[string]any:
+		return "array"
+	case time.Time:
+		return "datetime"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func typeMatches(schemaType string, value any) bool {
+	actual := valueType(value)
+	return schemaType == actual || (schemaType == "number" && actual == "integer")
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	schema := Schema()
+
+	t.Run("tools are any other table", func(t *testing.T) {
+		tool, ok := schema.AdditionalProperties.(*JSONSchema)
+		if !ok || tool.Ref != "#/definitions/ToolConfig" {
+			t.Fatalf("expected tool tables to reference ToolConfig, got %+v", schema.AdditionalProperties)
+		}
+		for _, section := range []string{"chex", "env", "files", "services"} {
+			if schema.Properties[section] == nil {
+				t.Errorf("expected [%s] to be described", section)
+			}
+		}
+	})
+
+	t.Run("every setting is described", func(t *testing.T) {
+		for name, def := range schema.Definitions {
+			for key, property := range def.Properties {
+				if property.Description == "" {
+					t.Errorf("expected a description for %s.%s", name, key)
+				}
+				if property.Ref != "" && property.Description != "" {
+					t.Errorf("description of %s.%s is next to $ref, where draft-07 ignores it", name, key)
+				}
+			}
+		}
+		for key := range schemaEnums {
+			name, property, _ := strings.Cut(key, ".")
+			if def := schema.Definitions[name]; def == nil || def.Properties[property] == nil {
+				t.Errorf("enum for unknown setting %s", key)
+			}
+		}
+	})
+
+	t.Run("descriptions belong to settings", func(t *testing.T) {
+		types := configTypes(reflect.TypeFor[ChexConfig](), reflect.TypeFor[ToolConfig](),
+			reflect.TypeFor[EnvConfig](), reflect.TypeFor[FileConfig](), reflect.TypeFor[ServiceConfig]())
+		if len(types) != len(schema.Definitions) {
+			t.Errorf("expected a definition for each of %d config structs, got %d", len(types), len(schema.Definitions))
+		}
+
+		for _, typ := range types {
+			for i := range typ.NumField() {
+				field := typ.Field(i)
+				key, desc := field.Tag.Get("toml"), field.Tag.Get("desc")
+				if desc != "" && (key == "" || key == "-") {
+					t.Errorf("%s.%s has a description but no key", typ.Name(), field.Name)
+				}
+				// Descriptions name settings by key; Go field names mean nothing to users
+				for j := range typ.NumField() {
+					other := typ.Field(j)
+					if other.Tag.Get("toml") != other.Name && slices.Contains(strings.Fields(desc), other.Name) {
+						t.Errorf("description of %s.%s names the field %s", typ.Name(), key, other.Name)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("required keys come from struct tags", func(t *testing.T) {
+		expected := map[string][]string{
+			"ToolConfig":   nil,
+			"Subcommand":   {"name"},
+			"CommandCheck": {"command"},
+			"FileConfig":   {"path"},
+			"Source":       {"path", "type"},
+		}
+		for name, required := range expected {
+			if got := schema.Definitions[name].Required; !slices.Equal(got, required) {
+				t.Errorf("expected %s to require %v, got %v", name, required, got)
+			}
+		}
+	})
+
+	t.Run("enums list accepted values", func(t *testing.T) {
+		sourceType := schema.Definitions["Source"].Properties["type"]
+		if !slices.Equal(sourceType.Enum, []string{"chex", "mise", "tool-versions"}) {
+			t.Errorf("unexpected source type enum: %v", sourceType.Enum)
+		}
+		scheme := schema.Definitions["ToolConfig"].Properties["version_scheme"]
+		if !slices.Contains(scheme.Enum, "pep440") {
+			t.Errorf("unexpected version_scheme enum: %v", scheme.Enum)
+		}
+	})
+
+	t.Run("alternatives accept a plain string", func(t *testing.T) {
+		items := schema.Definitions["ToolConfig"].Properties["alternatives"].Items
+		if len(items.AnyOf) != 2 || items.AnyOf[0].Type != "string" ||
+			items.AnyOf[1].Ref != "#/definitions/Alternative" {
+			t.Errorf("unexpected alternatives schema: %+v", items)
+		}
+	})
+
+	t.Run("encodes as JSON", func(t *testing.T) {
+		data, err := json.Marshal(schema)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+
+		var decoded map[string]any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if decoded["$schema"] != "http://json-schema.org/draft-07/schema#" {
+			t.Errorf("unexpected $schema: %v", decoded["$schema"])
+		}
+		toolConfig := decoded["definitions"].(map[string]any)["ToolConfig"].(map[string]any)
+		if toolConfig["additionalProperties"] != false {
+			t.Errorf("expected tools to reject unknown keys, got %v", toolConfig["additionalProperties"])
+		}
+	})
+}
+
+func TestSchemaValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    map[string]any
+		expected []string
+	}{
+		{
+			name: "valid",
+			value: map[string]any{
+				"go": map[string]any{
+					"cli":          "go",
+					"alternatives": []any{"gotip", map[string]any{"cli": "go1.22"}},
+					"checks":       []map[string]any{{"command": "go env", "exit_code": int64(0)}},
+				},
+				"env": map[string]any{"HOME": map[string]any{"is_dir": true}},
+			},
+		},
+		{
+			name: "wrong types",
+			value: map[string]any{
+				"go": map[string]any{
+					"optional":     "yes",
+					"alternatives": []any{int64(1)},
+				},
+			},
+			expected: []string{
+				"go.alternatives: expected string or object, got integer",
+				"go.optional: expected boolean, got string",
+			},
+		},
+		{
+			name: "missing required key",
+			value: map[string]any{
+				"go": map[string]any{"checks": []any{map[string]any{"name": "env"}}},
+			},
+			expected: []string{`go.checks: missing required key "command"`},
+		},
+		{
+			name: "unknown keys and values",
+			value: map[string]any{
+				"chex": map[string]any{"merge_strategy": "newest", "source": []any{}},
+			},
+			expected: []string{
+				`unknown merge_strategy "newest" (expected first-wins, intersect, strictest)`,
+				`unknown key "chex.source" (did you mean "sources"?)`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			sv := &schemaValidator{
+				root: Schema(),
+				report: func(_ []string, format string, args ...any) {
+					messages = append(messages, fmt.Sprintf(format, args...))
+				},
+			}
+			sv.validate(sv.root, tt.value, nil)
+
+			if !slices.Equal(messages, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, messages)
+			}
+		})
+	}
+}
+
+// configTypes returns the structs reachable from the given config types.
+func configTypes(roots ...reflect.Type) []reflect.Type {
+	var types []reflect.Type
+	var visit func(reflect.Type)
+	visit = func(t reflect.Type) {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map:
+			visit(t.Elem())
+		case reflect.Struct:
+			if slices.Contains(types, t) {
+				return
+			}
+			types = append(types, t)
+			for i := range t.NumField() {
+				if field := t.Field(i); field.IsExported() {
+					visit(field.Type)
+				}
+			}
+		}
+	}
+	for _, root := range roots {
+		visit(root)
+	}
+	return types
+}
@@ -0,0 +1,326 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Version schemes a tool's version can be compared with.
+const (
+	SchemeSemver = "semver"
+	SchemeCalver = "calver"
+	SchemePEP440 = "pep440"
+	SchemeLoose  = "loose"
+	SchemeDate   = "date"
+)
+
+var (
+	pep440Re = regexp.MustCompile(`^(?i)v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+		`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d*))?` +
+		`(?:[-_.]?(post|rev|r)[-_.]?(\d*))?(?:[-_.]?(dev)[-_.]?(\d*))?$`)
+	looseRe  = regexp.MustCompile(`^v?(\d+(?:\.\d+)*)(?:-([0-9A-Za-z.]+))?$`)
+	calverRe = regexp.MustCompile(`^v?((?:\d{4}|\d{2})\.\d{1,2}(?:\.\d+)*)(?:-([0-9A-Za-z.]+))?$`)
+	dateRe   = regexp.MustCompile(`^(\d{4})-?(\d{2})-?(\d{2})$`)
+	// Any run of operator characters is captured so unknown operators are rejected
+	// rather than skipped
+	termRe    = regexp.MustCompile(`([<>=~!^]*)\s*([^\s,<>=~!^][^\s,]*)`)
+	pep440Pre = map[string]int{"a": 0, "alpha": 0, "b": 1, "beta": 1, "c": 2, "rc": 2, "pre": 2, "preview": 2}
+)
+
+// schemeOperators are the comparisons accepted by non-semver constraints.
+var schemeOperators = []string{"==", "!=", ">", ">=", "<", "<=", "~="}
+
+// VersionConstraint is a constraint parsed under a non-semver version scheme.
+type VersionConstraint struct {
+	scheme string
+	groups [][]versionTerm // OR-ed groups of AND-ed terms
+}
+
+// schemeVersion is a parsed non-semver version. Versions compare by epoch, then
+// release segments (zero-padded, so 1.2 == 1.2.0), then tail, then label.
+type schemeVersion struct {
+	epoch   int
+	release []int
+	tail    []int  // scheme-specific ordering after the release, e.g. PEP 440 pre/post/dev
+	label   string // pre-release label for loose and calver versions
+}
+
+// versionTerm is a single comparison in a constraint, e.g. ">=3.12" or "==2024.*".
+type versionTerm struct {
+	op      string
+	version *schemeVersion
+	prefix  []int // release prefix for wildcard (".*") and compatible release (~=) terms
+}
+
+// ParseVersionConstraint parses a constraint under the calver, pep440, loose or date
+// scheme into OR-ed ("||") groups of AND-ed terms. Terms are separated by commas or
+// spaces, e.g. ">=3.10, <3.13" or ">=2024.01 || ==2023.*".
+func ParseVersionConstraint(scheme, constraint string) (*VersionConstraint, error) {
+	if !slices.Contains([]string{SchemeCalver, SchemePEP440, SchemeLoose, SchemeDate}, scheme) {
+		return nil, fmt.Errorf("unknown version scheme %q", scheme)
+	}
+
+	c := &VersionConstraint{scheme: scheme}
+	for group := range strings.SplitSeq(constraint, "||") {
+		if rest := strings.Trim(termRe.ReplaceAllString(group, ""), " \t,"); rest != "" {
+			return nil, fmt.Errorf("unexpected %q in constraint", rest)
+		}
+
+		var terms []versionTerm
+		for _, match := range termRe.FindAllStringSubmatch(group, -1) {
+			term, err := parseVersionTerm(scheme, match[1], match[2])
+			if err != nil {
+				return nil, err
+			}
+			terms = append(terms, term)
+		}
+		if len(terms) == 0 {
+			return nil, errors.New("empty constraint")
+		}
+		c.groups = append(c.groups, terms)
+	}
+	return c, nil
+}
+
+// Check reports whether version satisfies the constraint.
+func (c *VersionConstraint) Check(version string) (bool, error) {
+	v, err := parseSchemeVersion(c.scheme, version)
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(c.groups, func(terms []versionTerm) bool {
+		return !slices.ContainsFunc(terms, func(term versionTerm) bool { return !term.matches(v) })
+	}), nil
+}
+
+// parseVersionTerm parses one operator and version. A bare version means "==".
+func parseVersionTerm(scheme, op, text string) (versionTerm, error) {
+	if op == "" || op == "=" {
+		op = "=="
+	}
+	if !slices.Contains(schemeOperators, op) {
+		return versionTerm{}, fmt.Errorf(
+			"unknown operator %q in %q (expected %s)", op, op+text, strings.Join(schemeOperators, ", "),
+		)
+	}
+
+	if prefix, ok := strings.CutSuffix(text, ".*"); ok {
+		if op != "==" && op != "!=" {
+			return versionTerm{}, fmt.Errorf("wildcard %q is only allowed with == or !=", text)
+		}
+		parts, err := parseRelease(strings.TrimPrefix(prefix, "v"))
+		if err != nil {
+			return versionTerm{}, err
+		}
+		return versionTerm{op: op, prefix: parts}, nil
+	}
+
+	version, err := parseSchemeVersion(scheme, text)
+	if err != nil {
+		return versionTerm{}, err
+	}
+	term := versionTerm{op: op, version: version}
+
+	if op == "~=" {
+		if len(version.release) < 2 {
+			return versionTerm{}, fmt.Errorf("~=%s needs at least two release segments", text)
+		}
+		term.prefix = version.release[:len(version.release)-1]
+	}
+	return term, nil
+}
+
+// matches reports whether v satisfies the term.
+func (term versionTerm) matches(v *schemeVersion) bool {
+	if term.version == nil {
+		// Wildcard: compare the release prefix only
+		matched := hasReleasePrefix(v.release, term.prefix)
+		return matched == (term.op == "==")
+	}
+
+	cmp := compareSchemeVersions(v, term.version)
+	switch term.op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case "~=":
+		return cmp >= 0 && hasReleasePrefix(v.release, term.prefix)
+	default:
+		return false
+	}
+}
+
+// parseSchemeVersion parses a version string under a non-semver scheme.
+func parseSchemeVersion(scheme, text string) (*schemeVersion, error) {
+	switch scheme {
+	case SchemePEP440:
+		return parsePEP440(text)
+	case SchemeDate:
+		return parseDateVersion(text)
+	case SchemeCalver:
+		return parseCalver(text)
+	default:
+		return parseLooseVersion(text)
+	}
+}
+
+// parseLooseVersion parses any number of dotted numeric segments with an optional
+// "-label" pre-release suffix, e.g. 1.2.3.4 or 2024.03.1-beta.
+func parseLooseVersion(text string) (*schemeVersion, error) {
+	m := looseRe.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil {
+		return nil, fmt.Errorf("%q is not a dotted numeric version", text)
+	}
+	return labeledVersion(m[1], m[2])
+}
+
+// parseCalver parses a calendar version: a YYYY or YY year, a month that may be
+// zero-padded (0M), then any further segments such as a day or micro version, with
+// an optional "-label" pre-release suffix, e.g. 2024.03, 24.04.1 or 2024.03.1-beta.
+func parseCalver(text string) (*schemeVersion, error) {
+	m := calverRe.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil {
+		return nil, fmt.Errorf("%q is not a calendar version (YYYY.0M or YY.0M, then any further segments)", text)
+	}
+
+	v, err := labeledVersion(m[1], m[2])
+	if err != nil {
+		return nil, err
+	}
+	if month := v.release[1]; month < 1 || month > 12 {
+		return nil, fmt.Errorf("%q has an invalid month %d", text, month)
+	}
+	return v, nil
+}
+
+// labeledVersion builds a version from its release segments and optional pre-release
+// label, which sorts before the release itself.
+func labeledVersion(releaseText, label string) (*schemeVersion, error) {
+	release, err := parseRelease(releaseText)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &schemeVersion{release: release, tail: []int{1}, label: label}
+	if label != "" {
+		v.tail = []int{0}
+	}
+	return v, nil
+}
+
+// parsePEP440 parses a PEP 440 version such as 3.12.0rc1, 1.0.post2 or 2!1.0.dev3.
+func parsePEP440(text string) (*schemeVersion, error) {
+	m := pep440Re.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil {
+		return nil, fmt.Errorf("%q is not a PEP 440 version", text)
+	}
+
+	release, err := parseRelease(m[2])
+	if err != nil {
+		return nil, err
+	}
+	v := &schemeVersion{release: release}
+	v.epoch, _ = strconv.Atoi(m[1])
+
+	hasPre, hasPost, hasDev := m[3] != "", m[5] != "", m[7] != ""
+	preNum, _ := strconv.Atoi(m[4])
+	postNum, _ := strconv.Atoi(m[6])
+	devNum, _ := strconv.Atoi(m[8])
+
+	// Order within a release: X.devN < XaN < XbN < XrcN < X < X.postN; a .devN
+	// suffix sorts before the same version without it
+	preRank := 3
+	switch {
+	case hasPre:
+		preRank = pep440Pre[strings.ToLower(m[3])]
+	case hasDev && !hasPost:
+		preRank = -1
+	}
+	postRank, devRank := 0, 1
+	if hasPost {
+		postRank = 1
+	}
+	if hasDev {
+		devRank = 0
+	}
+
+	v.tail = []int{preRank, preNum, postRank, postNum, devRank, devNum}
+	return v, nil
+}
+
+// parseDateVersion parses a YYYYMMDD or YYYY-MM-DD date version.
+func parseDateVersion(text string) (*schemeVersion, error) {
+	m := dateRe.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil {
+		return nil, fmt.Errorf("%q is not a YYYYMMDD or YYYY-MM-DD date", text)
+	}
+
+	year, _ := strconv.Atoi(m[1])
+	month, _ := strconv.Atoi(m[2])
+	day, _ := strconv.Atoi(m[3])
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return nil, fmt.Errorf("%q is not a valid date", text)
+	}
+	return &schemeVersion{release: []int{year, month, day}}, nil
+}
+
+// parseRelease parses dotted numeric segments, allowing leading zeros (2024.03).
+func parseRelease(text string) ([]int, error) {
+	var release []int
+	for part := range strings.SplitSeq(text, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version segment %q in %q", part, text)
+		}
+		release = append(release, n)
+	}
+	return release, nil
+}
+
+// compareSchemeVersions returns a negative number, zero or a positive number when a
+// sorts before, equal to or after b.
+func compareSchemeVersions(a, b *schemeVersion) int {
+	if a.epoch != b.epoch {
+		return a.epoch - b.epoch
+	}
+	for i := range max(len(a.release), len(b.release)) {
+		if x, y := segment(a.release, i), segment(b.release, i); x != y {
+			return x - y
+		}
+	}
+	if cmp := slices.Compare(a.tail, b.tail); cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(a.label, b.label)
+}
+
+// hasReleasePrefix reports whether release starts with prefix, zero-padding release.
+func hasReleasePrefix(release, prefix []int) bool {
+	for i, want := range prefix {
+		if segment(release, i) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// segment returns release[i], or 0 past the end.
+func segment(release []int, i int) int {
+	if i < len(release) {
+		return release[i]
+	}
+	return 0
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseVersionConstraint(t *testing.T) {
+	tests := []struct {
+		name        string
+		scheme      string
+		constraint  string
+		installed   string
+		expected    bool
+		errContains string
+	}{
+		{
+			name:       "calver zero-padded month",
+			scheme:     SchemeCalver,
+			constraint: ">=2024.03",
+			installed:  "2024.10",
+			expected:   true,
+		},
+		{
+			name:       "calver short year",
+			scheme:     SchemeCalver,
+			constraint: "==24.04.*",
+			installed:  "24.04.1",
+			expected:   true,
+		},
+		{
+			name:        "calver rejects versions that aren't dates",
+			scheme:      SchemeCalver,
+			constraint:  ">=2024.01",
+			installed:   "1.2.3",
+			errContains: "not a calendar version",
+		},
+		{
+			name:        "calver rejects invalid months",
+			scheme:      SchemeCalver,
+			constraint:  ">=2024.13",
+			errContains: "invalid month 13",
+		},
+		{
+			name:        "tilde",
+			scheme:      SchemeLoose,
+			constraint:  "~1.2",
+			errContains: `unknown operator "~"`,
+		},
+		{
+			name:        "bang",
+			scheme:      SchemePEP440,
+			constraint:  "!1.2",
+			errContains: `unknown operator "!"`,
+		},
+		{
+			name:        "caret",
+			scheme:      SchemeDate,
+			constraint:  "^20240101",
+			errContains: `unknown operator "^"`,
+		},
+		{
+			name:        "doubled operator",
+			scheme:      SchemeLoose,
+			constraint:  ">=1.0, >==2.0",
+			errContains: `unknown operator ">=="`,
+		},
+		{
+			name:        "trailing operator",
+			scheme:      SchemeLoose,
+			constraint:  ">=1.0 <",
+			errContains: `unexpected "<"`,
+		},
+		{
+			name:        "semver isn't a scheme constraint",
+			scheme:      SchemeSemver,
+			constraint:  ">=1.0",
+			errContains: "unknown version scheme",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseVersionConstraint(tt.scheme, tt.constraint)
+			if err == nil && tt.installed != "" {
+				var satisfied bool
+				satisfied, err = c.Check(tt.installed)
+				if err == nil && satisfied != tt.expected {
+					t.Errorf("expected %v, got %v", tt.expected, satisfied)
+				}
+			}
+
+			if tt.errContains == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.errContains != "" && (err == nil || !strings.Contains(err.Error(), tt.errContains)) {
+				t.Errorf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
@@ -1,44 +1,295 @@
 package config
 
+import "fmt"
+
 // Config represents the complete chex configuration.
 type Config struct {
-	Chex  *ChexConfig
-	Tools map[string]ToolConfig
+	File     string // path the configuration was loaded from
+	Chex     *ChexConfig
+	Tools    map[string]ToolConfig
+	Order    []string // tool names in the order they're declared
+	Env      map[string]EnvConfig
+	Files    map[string]FileConfig
+	Services map[string]ServiceConfig
+	Unknown  []Diagnostic // keys that don't match any setting
+
+	positions keyPositions
+}
+
+// Position returns where the key at path is defined, falling back to its closest
+// defined parent table.
+func (c *Config) Position(path ...string) Position {
+	return c.positions.lookup(path...)
 }
 
 // ChexConfig represents the [chex] section of the configuration.
 type ChexConfig struct {
-	Sources            []Source `toml:"sources"`
-	FailOnUnknownTools bool     `toml:"fail_on_unknown_tools"` // Default: false
-	SkipUnknownTools   bool     `toml:"skip_unknown_tools"`    // Default: false
-	WarnOnUnknownTools bool     `toml:"warn_on_unknown_tools"` // Default: true
+	Sources            []Source `toml:"sources" desc:"external sources (default: mise.toml and .tool-versions)"`
+	FailOnUnknownTools bool     `toml:"fail_on_unknown_tools" desc:"fail on source tools chex can't map (default: false)"`
+	SkipUnknownTools   bool     `toml:"skip_unknown_tools" desc:"ignore source tools chex can't map (default: false)"`
+	WarnOnUnknownTools bool     `toml:"warn_on_unknown_tools" desc:"warn on source tools chex can't map (default: true)"`
+	AllowPrerelease    bool     `toml:"allow_prerelease" desc:"let prereleases satisfy constraints (default: false)"`
+	MergeStrategy      string   `toml:"merge_strategy" desc:"how constraints from sources combine (default: first-wins)"`
 }
 
 // Source represents an external configuration source.
 type Source struct {
-	Path string `toml:"path"`
-	Type string `toml:"type"` // "chex", "mise", "tool-versions"
+	Path string `toml:"path" jsonschema:"required" desc:"file to load, relative to the project root (${VAR} is expanded)"`
+	Type string `toml:"type" jsonschema:"required" desc:"kind of file to load"`
 }
 
+// Source types that [chex] sources can load.
+const (
+	SourceChex         = "chex"
+	SourceMise         = "mise"
+	SourceToolVersions = "tool-versions"
+)
+
+// Version sources a tool's version can be read from.
+const (
+	VersionSourceCommand   = "command"
+	VersionSourceBuildInfo = "buildinfo"
+)
+
+// Built-in version presets for tool families with unusual version output.
+const (
+	PresetJava    = "java"
+	PresetPython2 = "python2"
+	PresetRuby    = "ruby"
+)
+
+// Version and package managers that must_be_managed_by accepts.
+const (
+	ManagerMise     = "mise"
+	ManagerAsdf     = "asdf"
+	ManagerHomebrew = "homebrew"
+)
+
 // ToolConfig represents a tool definition from the configuration file.
 type ToolConfig struct {
-	Name           string `toml:"name"`            // optional: override display name
-	CLI            string `toml:"cli"`             // required: command to execute
-	Version        string `toml:"version"`         // optional: version constraint
-	VersionArg     string `toml:"version_arg"`     // optional: argument to get version
-	VersionPattern string `toml:"version_pattern"` // optional: regex to extract version
-	Optional       bool   `toml:"optional"`        // optional: mark as optional
-	Message        string `toml:"message"`         // optional: custom message
+	Name            string         `toml:"name" desc:"override display name"`
+	CLI             string         `toml:"cli" desc:"command to execute (required without alternatives)"`
+	Version         string         `toml:"version" desc:"version constraint"`
+	VersionArg      string         `toml:"version_arg" desc:"arguments to get version, split like a shell"`
+	VersionArgs     []string       `toml:"version_args" desc:"arguments to get version, one per item"`
+	Shell           bool           `toml:"shell" desc:"run the version command with sh -c"`
+	VersionPattern  string         `toml:"version_pattern" desc:"regex to extract version"`
+	VersionSource   string         `toml:"version_source" desc:"where the version is read from (default: command)"`
+	VersionJSON     string         `toml:"version_json_path" desc:"path to the version in JSON output"`
+	VersionYAML     string         `toml:"version_yaml_path" desc:"path to the version in YAML output"`
+	VersionScheme   string         `toml:"version_scheme" desc:"how versions are parsed and compared (default: semver)"`
+	VersionPreset   string         `toml:"version_preset" desc:"built-in normalizer for a tool family"`
+	VersionReplace  []Replacement  `toml:"version_replace" desc:"regex rewrites of the extracted version"`
+	VersionCoerce   bool           `toml:"version_coerce" desc:"pad short versions, e.g. 21 -> 21.0.0"`
+	AllowPrerelease *bool          `toml:"allow_prerelease" desc:"let prereleases satisfy the constraint"`
+	Optional        bool           `toml:"optional" desc:"mark as optional"`
+	Message         string         `toml:"message" desc:"custom message"`
+	When            *Condition     `toml:"when" desc:"only require the tool when met"`
+	Alternatives    []Alternative  `toml:"alternatives" desc:"other CLIs that satisfy the requirement"`
+	Checks          []CommandCheck `toml:"checks" desc:"commands to run after the version check"`
+	Subcommands     []Subcommand   `toml:"subcommands" desc:"subcommands that must be available"`
+	Plugins         []Subcommand   `toml:"plugins" desc:"alias of subcommands for plugins"`
+	PathPattern     string         `toml:"path_pattern" desc:"regex the resolved binary path must match"`
+	ManagedBy       string         `toml:"must_be_managed_by" desc:"version manager that must provide the binary"`
+	SHA256          string         `toml:"sha256" desc:"expected checksum of the binary"`
+	SHA256File      string         `toml:"sha256_file" desc:"checksums file listing the binary"`
+	Arch            string         `toml:"arch" desc:"required binary architecture, or \"native\""`
+	Static          *bool          `toml:"static" desc:"require static (true) or dynamic linking"`
+
+	// How the version command, subcommands and checks are run
+	Env      map[string]string `toml:"env" desc:"variables set for the tool's commands"`
+	ClearEnv []string          `toml:"clear_env" desc:"variables removed for the tool's commands"`
+	Workdir  string            `toml:"workdir" desc:"directory to run the tool's commands in"`
+}
+
+// Subcommand represents a subcommand or plugin that must be available on a tool,
+// such as `docker compose` or `kubectl krew`.
+type Subcommand struct {
+	Name           string `toml:"name" jsonschema:"required" desc:"subcommand to probe, e.g. \"compose\""`
+	Version        string `toml:"version" desc:"version constraint"`
+	VersionArg     string `toml:"version_arg" desc:"argument after the subcommand to get version"`
+	VersionPattern string `toml:"version_pattern" desc:"regex to extract version"`
+	Optional       bool   `toml:"optional" desc:"mark as optional"`
+}
+
+// CommandCheck represents a custom command that must succeed for a tool to pass.
+type CommandCheck struct {
+	Name          string `toml:"name" desc:"display name (default: command)"`
+	Command       string `toml:"command" jsonschema:"required" desc:"command line to run, split like a shell"`
+	Shell         bool   `toml:"shell" desc:"run the command with sh -c"`
+	ExitCode      int    `toml:"exit_code" desc:"expected exit code (default: 0)"`
+	StdoutPattern string `toml:"stdout_pattern" desc:"regex stdout must match"`
+	Timeout       string `toml:"timeout" desc:"duration such as \"10s\" (default: 5s)"`
+}
+
+// Replacement rewrites part of an extracted version. From is a regular expression
+// and To may reference its groups ($1).
+type Replacement struct {
+	From string `toml:"from" jsonschema:"required" desc:"regex to replace"`
+	To   string `toml:"to" desc:"replacement, may reference groups ($1)"`
+}
+
+// Alternative represents another CLI that can satisfy a tool requirement.
+// Empty fields inherit the value from the tool definition, except the checksums,
+// which only apply to the tool's own CLI.
+type Alternative struct {
+	CLI            string `toml:"cli" desc:"command to execute instead"`
+	Version        string `toml:"version" desc:"version constraint"`
+	VersionArg     string `toml:"version_arg" desc:"arguments to get version"`
+	VersionPattern string `toml:"version_pattern" desc:"regex to extract version"`
+
+	unknownKeys []string // keys that don't match a field, reported as unknown keys
+}
+
+// UnmarshalTOML allows an alternative to be written as a plain CLI name or as a table.
+func (a *Alternative) UnmarshalTOML(data any) error {
+	switch v := data.(type) {
+	case string:
+		a.CLI = v
+	case map[string]any:
+		for key, value := range v {
+			s, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("alternative %s must be a string", key)
+			}
+			switch key {
+			case "cli":
+				a.CLI = s
+			case "version":
+				a.Version = s
+			case "version_arg":
+				a.VersionArg = s
+			case "version_pattern":
+				a.VersionPattern = s
+			default:
+				a.unknownKeys = append(a.unknownKeys, key)
+			}
+		}
+	default:
+		return fmt.Errorf("alternative must be a string or table, got %T", data)
+	}
+	return nil
+}
+
+// Condition represents a `when` expression that gates whether a tool is required.
+// All fields that are set must be satisfied for the condition to be met.
+type Condition struct {
+	Env        string `toml:"env" desc:"environment variable to inspect"`
+	Equals     string `toml:"equals" desc:"required value of env (empty = any non-empty value)"`
+	FileExists string `toml:"file_exists" desc:"file or directory that must exist"`
+}
+
+// EnvConfig represents an [env.NAME] section of the configuration.
+type EnvConfig struct {
+	Name     string `toml:"name" desc:"override display name"`
+	Pattern  string `toml:"pattern" desc:"regex the value must match"`
+	IsDir    bool   `toml:"is_dir" desc:"value must point to an existing directory"`
+	Optional bool   `toml:"optional" desc:"mark as optional"`
+	Message  string `toml:"message" desc:"custom message"`
+}
+
+// FileConfig represents a [files.NAME] section of the configuration.
+type FileConfig struct {
+	Name      string `toml:"name" desc:"override display name"`
+	Path      string `toml:"path" jsonschema:"required" desc:"file or directory to check (~ and ${VAR} are expanded)"`
+	Exists    *bool  `toml:"exists" desc:"false requires the path to be absent (default: true)"`
+	Mode      string `toml:"mode" desc:"required permission bits in octal, e.g. \"0600\""`
+	Contains  string `toml:"contains" desc:"regex the file content must match"`
+	NewerThan string `toml:"newer_than" desc:"path the file must have been modified after"`
+	Optional  bool   `toml:"optional" desc:"mark as optional"`
+	Message   string `toml:"message" desc:"custom message"`
+}
+
+// ServiceConfig represents a [services.NAME] section of the configuration.
+type ServiceConfig struct {
+	Name       string `toml:"name" desc:"override display name"`
+	Address    string `toml:"address" desc:"TCP host:port to connect to"`
+	Socket     string `toml:"socket" desc:"Unix socket path to connect to (instead of address)"`
+	Timeout    string `toml:"timeout" desc:"duration such as \"500ms\" (default: 2s)"`
+	HTTPPath   string `toml:"http_path" desc:"path for an HTTP GET after connecting (a leading / is added)"`
+	HTTPStatus int    `toml:"http_status" desc:"expected HTTP status (default: 200)"`
+	Optional   bool   `toml:"optional" desc:"mark as optional"`
+	Message    string `toml:"message" desc:"custom message"`
 }
 
 // Tool represents a processed tool ready for checking.
 type Tool struct {
-	Name           string // display name
-	CLI            string // command to execute
-	Version        string // version constraint (empty = existence check only)
-	VersionArg     string // argument to get version (default: "version" or "--version")
-	VersionPattern string // regex to extract version
-	Optional       bool   // whether tool is optional
-	Message        string // custom message
-	Source         string // where tool was defined ("config", "mise", "tool-versions")
+	Name            string             // display name
+	CLI             string             // command to execute
+	Version         string             // version constraint (empty = existence check only)
+	VersionArg      string             // arguments to get version (default: "version" or "--version")
+	VersionArgs     []string           // arguments to get version, used instead of VersionArg when set
+	Shell           bool               // run the CLI and version arguments as a sh -c command line
+	Env             map[string]string  // variables set for the tool's commands
+	ClearEnv        []string           // variables removed from the tool's commands' environment
+	Workdir         string             // absolute directory the tool's commands run in (empty = current)
+	VersionPattern  string             // regex to extract version
+	VersionSource   string             // where the version is read from ("command" or "buildinfo")
+	VersionJSON     string             // path to the version in JSON output, e.g. ".clientVersion.gitVersion"
+	VersionYAML     string             // path to the version in YAML output
+	VersionScheme   string             // how versions are parsed and compared (default: semver)
+	VersionPreset   string             // built-in normalizer for a tool family
+	VersionReplace  []Replacement      // regex replacements applied to the extracted version
+	VersionCoerce   bool               // pad short versions to three segments
+	AllowPrerelease *bool              // let prereleases satisfy the constraint (nil = [chex] default)
+	Constraints     []SourceConstraint // version constraints from every source that defined the tool
+	Optional        bool               // whether tool is optional
+	Message         string             // custom message
+	Source          string             // where tool was defined ("config", "mise", "tool-versions")
+	File            string             // file the tool was defined in
+	Position        Position           // where the tool's definition starts in File
+	When            *Condition         // condition under which the tool is required (nil = always)
+	Alternatives    []Alternative      // other CLIs that satisfy the requirement
+	Checks          []CommandCheck     // commands to run after the version check
+	Subcommands     []Subcommand       // subcommands and plugins that must be available
+	PathPattern     string             // regex the resolved binary path must match
+	ManagedBy       string             // version manager that must provide the binary
+	SHA256          string             // expected checksum of the binary
+	SHA256File      string             // checksums file listing the binary (sha256sum format)
+	Arch            string             // required binary architecture, or "native"
+	Static          *bool              // required linking, nil if unchecked
+	ConfigError     error              // problem with the definition, such as a variable that can't be expanded
+}
+
+// Location returns where the tool is defined as file:line:col, or "" if unknown.
+func (t *Tool) Location() string {
+	if t.File == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d:%d", t.File, t.Position.Line, t.Position.Col)
+}
+
+// EnvRequirement represents a processed environment variable requirement ready for checking.
+type EnvRequirement struct {
+	Name     string // display name
+	Variable string // environment variable to check
+	Pattern  string // regex the value must match
+	IsDir    bool   // whether the value must point to an existing directory
+	Optional bool   // whether the variable is optional
+	Message  string // custom message
+}
+
+// FileRequirement represents a processed file or directory requirement ready for checking.
+type FileRequirement struct {
+	Name        string // display name
+	Path        string // absolute path to check
+	Exists      bool   // whether the path must exist (false = must be absent)
+	Mode        string // required permission bits in octal
+	Contains    string // regex the file content must match
+	NewerThan   string // absolute path the file must have been modified after
+	Optional    bool   // whether the file is optional
+	Message     string // custom message
+	ConfigError error  // problem with the definition, such as a variable that can't be expanded
+}
+
+// ServiceRequirement represents a processed local service requirement ready for checking.
+type ServiceRequirement struct {
+	Name       string // display name
+	Address    string // TCP host:port to connect to
+	Socket     string // absolute Unix socket path to connect to
+	Timeout    string // connection timeout
+	HTTPPath   string // path for an HTTP GET handshake (empty = connect only)
+	HTTPStatus int    // expected HTTP status
+	Optional   bool   // whether the service is optional
+	Message    string // custom message
 }
@@ -0,0 +1,326 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Severity is how serious a validation diagnostic is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a problem found while validating a configuration file.
+type Diagnostic struct {
+	File string
+	Position
+	Severity Severity
+	Message  string
+
+	keys []string // config keys the diagnostic is about, when known
+}
+
+// String formats the diagnostic as file:line:col: severity: message.
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s", d.File, d.Line, d.Col, d.Severity, d.Message)
+}
+
+// HasErrors reports whether any of the diagnostics is an error.
+func HasErrors(diagnostics []Diagnostic) bool {
+	return slices.ContainsFunc(diagnostics, func(d Diagnostic) bool {
+		return d.Severity == SeverityError
+	})
+}
+
+// Validate checks a configuration file without running anything, returning a
+// diagnostic for every problem found, ordered by position. It only returns an
+// error when the file can't be read.
+func Validate(path, rootDir string) ([]Diagnostic, error) {
+	path, rootDir = configLocation(path, rootDir)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	v := &validator{file: path, rootDir: rootDir}
+
+	// Syntax errors stop validation; the schema covers everything else the decoder
+	// could complain about, such as unknown keys and values of the wrong type
+	cfg, raw, problems := decodeConfig(path, data)
+	if cfg == nil {
+		return problems, nil
+	}
+	v.positions = cfg.positions
+
+	sv := &schemaValidator{root: Schema(), report: v.errorf}
+	sv.validate(sv.root, raw, nil)
+
+	// Check the values chex will use, reporting variables that aren't defined
+	v.diagnostics = append(v.diagnostics, expandConfig(cfg, rootDir)...)
+
+	v.checkChex(cfg.Chex)
+	v.checkTools(cfg)
+	for name, env := range cfg.Env {
+		v.checkPattern(env.Pattern, "env", name, "pattern")
+	}
+	for name, file := range cfg.Files {
+		v.checkPattern(file.Contains, "files", name, "contains")
+	}
+
+	// Conflicts between sources only show up once everything is merged
+	if !HasErrors(v.diagnostics) {
+		v.checkConflicts(path, rootDir)
+	}
+
+	slices.SortStableFunc(v.diagnostics, func(a, b Diagnostic) int {
+		return a.Position.compare(b.Position)
+	})
+
+	return v.diagnostics, nil
+}
+
+// validator collects diagnostics for a single configuration file.
+type validator struct {
+	file        string
+	rootDir     string
+	positions   keyPositions
+	diagnostics []Diagnostic
+}
+
+func (v *validator) add(pos Position, severity Severity, format string, args ...any) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{
+		File:     v.file,
+		Position: pos,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) errorf(path []string, format string, args ...any) {
+	v.add(v.positions.lookup(path...), SeverityError, format, args...)
+}
+
+// checkChex validates the [chex] section's settings and sources.
+func (v *validator) checkChex(chex *ChexConfig) {
+	if chex == nil {
+		return
+	}
+
+	for _, source := range chex.Sources {
+		if strings.Contains(source.Path, "${") {
+			continue // failed to expand, already reported
+		}
+		sourcePath := source.Path
+		if !filepath.IsAbs(sourcePath) {
+			sourcePath = filepath.Join(v.rootDir, sourcePath)
+		}
+		if _, err := os.Stat(sourcePath); err != nil {
+			v.errorf([]string{"chex", "sources", "path"}, "source %q not found", source.Path)
+		}
+	}
+}
+
+// checkTools validates every tool, then looks for tools sharing a display name.
+func (v *validator) checkTools(cfg *Config) {
+	// Visit tools in file order so duplicates are reported on the later definition
+	displayNames := make(map[string]string)
+	for _, name := range cfg.Order {
+		tool := cfg.Tools[name]
+		v.checkTool(name, tool)
+
+		displayName := name
+		if tool.Name != "" {
+			displayName = tool.Name
+		}
+		if first, exists := displayNames[displayName]; exists {
+			v.errorf(
+				[]string{name, "name"},
+				"display name %q of [%s] is already used by [%s]", displayName, name, first,
+			)
+			continue
+		}
+		displayNames[displayName] = name
+	}
+}
+
+// checkTool validates a single tool definition.
+func (v *validator) checkTool(name string, tool ToolConfig) {
+	if tool.CLI == "" && len(tool.Alternatives) == 0 {
+		v.errorf([]string{name}, "[%s] has no cli", name)
+	}
+
+	scheme := tool.VersionScheme
+	v.checkConstraint(scheme, tool.Version, name, "version")
+	v.checkPattern(tool.VersionPattern, name, "version_pattern")
+	v.checkPattern(tool.PathPattern, name, "path_pattern")
+	v.checkWorkdir(tool.Workdir, name, "workdir")
+
+	for _, replacement := range tool.VersionReplace {
+		v.checkPattern(replacement.From, name, "version_replace", "from")
+	}
+	for _, alternative := range tool.Alternatives {
+		v.checkConstraint(scheme, alternative.Version, name, "alternatives", "version")
+		v.checkPattern(alternative.VersionPattern, name, "alternatives", "version_pattern")
+	}
+	for _, check := range tool.Checks {
+		v.checkPattern(check.StdoutPattern, name, "checks", "stdout_pattern")
+	}
+	v.checkCommands(name, tool)
+	for _, key := range []string{"subcommands", "plugins"} {
+		subcommands := tool.Subcommands
+		if key == "plugins" {
+			subcommands = tool.Plugins
+		}
+		for _, subcommand := range subcommands {
+			v.checkConstraint(scheme, subcommand.Version, name, key, "version")
+			v.checkPattern(subcommand.VersionPattern, name, key, "version_pattern")
+		}
+	}
+}
+
+// checkCommands validates the command lines a tool runs and warns about the ones
+// that go through the shell.
+func (v *validator) checkCommands(name string, tool ToolConfig) {
+	if tool.VersionArg != "" && len(tool.VersionArgs) > 0 {
+		v.errorf([]string{name, "version_args"}, "[%s] sets both version_arg and version_args", name)
+	}
+
+	if tool.Shell {
+		command := JoinArgs([]string{tool.CLI}) + " " + tool.VersionArg
+		if len(tool.VersionArgs) > 0 {
+			command = JoinArgs(append([]string{tool.CLI}, tool.VersionArgs...))
+		}
+		v.warnShell(command, name, "shell")
+	} else {
+		v.checkArgs(tool.VersionArg, name, "version_arg")
+		for _, alternative := range tool.Alternatives {
+			v.checkArgs(alternative.VersionArg, name, "alternatives", "version_arg")
+		}
+	}
+
+	for _, check := range tool.Checks {
+		if check.Shell {
+			v.warnShell(check.Command, name, "checks", "shell")
+		} else {
+			v.checkArgs(check.Command, name, "checks", "command")
+		}
+	}
+}
+
+// checkArgs reports command lines that can't be split into arguments, and warns
+// about shell operators that would be passed to the command as plain arguments.
+func (v *validator) checkArgs(command string, path ...string) {
+	args, err := SplitArgs(command)
+	if err != nil {
+		v.errorf(path, "invalid command line: %v", err)
+		return
+	}
+	for _, arg := range args {
+		if slices.Contains(shellOperators, arg) {
+			v.add(v.positions.lookup(path...), SeverityWarning,
+				"%q is passed as a plain argument; set shell = true to run %q as a shell command line", arg, command)
+			return
+		}
+	}
+}
+
+// warnShell reminds that shell command lines are interpreted by sh, so they run
+// whatever the config (and any variables expanded into it) says.
+func (v *validator) warnShell(command string, path ...string) {
+	v.add(v.positions.lookup(path...), SeverityWarning,
+		"shell = true runs %q with sh -c, which interprets quotes, variables, globs and operators; "+
+			"only use it in trusted config", command)
+}
+
+// checkWorkdir reports working directories that don't exist.
+func (v *validator) checkWorkdir(workdir string, path ...string) {
+	// Values still holding ${...} failed to expand and have been reported already
+	if workdir == "" || strings.Contains(workdir, "${") {
+		return
+	}
+	if info, err := os.Stat(resolvePath(workdir, v.rootDir)); err != nil || !info.IsDir() {
+		v.errorf(path, "workdir %q is not a directory", workdir)
+	}
+}
+
+// checkConstraint reports constraints that don't parse under the tool's version scheme,
+// and semver constraints that can't be met by any version.
+func (v *validator) checkConstraint(scheme, constraint string, path ...string) {
+	// Values still holding ${...} failed to expand and have been reported already
+	if constraint == "" || strings.Contains(constraint, "${") {
+		return
+	}
+
+	if scheme != "" && scheme != SchemeSemver {
+		// Unknown schemes are reported by the schema
+		if !slices.Contains(validVersionSchemes, scheme) {
+			return
+		}
+		if _, err := ParseVersionConstraint(scheme, constraint); err != nil {
+			v.errorf(path, "invalid version constraint %q: %v", constraint, err)
+		}
+		return
+	}
+
+	if _, err := semver.NewConstraint(constraint); err != nil {
+		v.errorf(path, "invalid version constraint %q: %v", constraint, err)
+		return
+	}
+
+	if r, err := parseRange(constraint); err == nil && len(r) == 0 {
+		v.errorf(path, "version constraint %q can never be satisfied", constraint)
+	}
+}
+
+// checkPattern reports regular expressions that don't compile.
+func (v *validator) checkPattern(pattern string, path ...string) {
+	if pattern == "" {
+		return
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		v.errorf(path, "invalid regular expression %q: %v", pattern, err)
+	}
+}
+
+// checkConflicts warns about tools whose constraints from different sources have no
+// version in common or can't be compared.
+func (v *validator) checkConflicts(path, rootDir string) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+	result, err := LoadAndMerge(absPath, rootDir)
+	if err != nil {
+		return
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(result.Tools)) {
+		tool := result.Tools[name]
+		ranges, err := constraintRanges(tool)
+		if err != nil {
+			// Like LoadAndMerge, only tools with a version of their own merge constraints
+			if tool.Version != "" {
+				v.add(v.positions.lookup(name, "version"), SeverityWarning,
+					"cannot merge version constraints for '%s': %v", tool.Name, err)
+			}
+			continue
+		}
+		if ranges == nil {
+			continue
+		}
+		if conflict := describeConflict(tool, ranges); conflict != "" {
+			v.add(v.positions.lookup(name, "version"), SeverityWarning, "%s", conflict)
+		}
+	}
+}
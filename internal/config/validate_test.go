@@ -0,0 +1,361 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string // default: .chex.toml
+		config   string
+		expected []string
+	}{
+		{
+			name: "valid config",
+			config: `
+[chex]
+merge_strategy = "strictest"
+
+[go]
+cli = "go"
+version = ">=1.20"
+version_pattern = 'go(\d+\.\d+)'
+
+[env.HOME]
+is_dir = true
+`,
+		},
+		{
+			name: "unknown keys with suggestions",
+			config: `
+[go]
+cli = "go"
+verison = ">=1.20"
+when = { env = "CI", equal = "true" }
+checks = [{ command = "go env", exit_cod = 0 }]
+frobnicate = true
+`,
+			expected: []string{
+				`4:1: error: unknown key "go.verison" (did you mean "version"?)`,
+				`5:22: error: unknown key "go.when.equal" (did you mean "equals"?)`,
+				`6:33: error: unknown key "go.checks.exit_cod" (did you mean "exit_code"?)`,
+				`7:1: error: unknown key "go.frobnicate"`,
+			},
+		},
+		{
+			name: "invalid constraints",
+			config: `
+[go]
+cli = "go"
+version = ">=banana"
+
+[node]
+cli = "node"
+version = ">=20 <18"
+`,
+			expected: []string{
+				`4:1: error: invalid version constraint ">=banana"`,
+				`8:1: error: version constraint ">=20 <18" can never be satisfied`,
+			},
+		},
+		{
+			name: "other schemes aren't parsed as semver",
+			config: `
+[python]
+cli = "python"
+version = "~=3.11"
+version_scheme = "pep440"
+`,
+		},
+		{
+			name: "invalid constraints in other schemes",
+			config: `
+[python]
+cli = "python"
+version = "~3.11"
+version_scheme = "pep440"
+
+[pip-tools]
+cli = "pip-compile"
+version = ">=2024.13"
+version_scheme = "calver"
+alternatives = [{ cli = "uv", version = "!2024.01" }]
+`,
+			expected: []string{
+				`4:1: error: invalid version constraint "~3.11": unknown operator "~"`,
+				`9:1: error: invalid version constraint ">=2024.13": "2024.13" has an invalid month 13`,
+				`11:31: error: invalid version constraint "!2024.01": unknown operator "!"`,
+			},
+		},
+		{
+			name: "invalid regular expressions",
+			config: `
+[go]
+cli = "go"
+version_pattern = "go(\\d+"
+version_replace = [{ from = "[", to = "" }]
+
+[env.TOKEN]
+pattern = "*"
+`,
+			expected: []string{
+				`4:1: error: invalid regular expression "go(\\d+"`,
+				`5:22: error: invalid regular expression "["`,
+				`8:1: error: invalid regular expression "*"`,
+			},
+		},
+		{
+			name: "missing cli and duplicate names",
+			config: `
+[go]
+cli = "go"
+
+[golang]
+name = "go"
+
+[docker]
+alternatives = ["podman"]
+`,
+			expected: []string{
+				`5:1: error: [golang] has no cli`,
+				`6:1: error: display name "go" of [golang] is already used by [go]`,
+			},
+		},
+		{
+			name: "unknown names",
+			config: `
+[chex]
+merge_strategy = "newest"
+
+[java]
+cli = "java"
+version_preset = "jdk"
+version_source = "binary"
+`,
+			expected: []string{
+				`3:1: error: unknown merge_strategy "newest"`,
+				`7:1: error: unknown version_preset "jdk"`,
+				`8:1: error: unknown version_source "binary"`,
+			},
+		},
+		{
+			name: "broken sources",
+			config: `
+[chex]
+sources = [
+  { path = "missing.toml", type = "mise" },
+  { path = ".chex.toml", type = "asdf" },
+]
+`,
+			expected: []string{
+				`4:5: error: source "missing.toml" not found`,
+				`4:28: error: unknown type "asdf" (expected chex, mise, tool-versions)`,
+			},
+		},
+		{
+			name: "wrong value type",
+			config: `
+[node]
+cli = "node"
+version = 18
+`,
+			expected: []string{
+				`4:1: error: node.version: expected string, got integer`,
+			},
+		},
+		{
+			name: "syntax error",
+			config: `
+[go
+cli = "go"
+`,
+			expected: []string{
+				`3:4: error: expected '.' or ']' to end table name`,
+			},
+		},
+		{
+			name: "command lines",
+			config: `
+[helm]
+cli = "helm"
+version_arg = "version --template '{{.Version}}"
+version_args = ["version"]
+
+[jq]
+cli = "jq"
+version_arg = "--version | cut -d- -f2"
+checks = [{ command = "jq -n 1 | grep 1", shell = true }]
+
+[kubectl]
+cli = "kubectl"
+version_arg = "version --client -o json"
+shell = true
+`,
+			expected: []string{
+				`4:1: error: invalid command line: unterminated '`,
+				`5:1: error: [helm] sets both version_arg and version_args`,
+				`9:1: warning: "|" is passed as a plain argument; set shell = true`,
+				`10:43: warning: shell = true runs "jq -n 1 | grep 1" with sh -c`,
+				`15:1: warning: shell = true runs "kubectl version --client -o json" with sh -c`,
+			},
+		},
+		{
+			name: "missing workdir",
+			config: `
+[go]
+cli = "go"
+workdir = "services/api"
+env = { GOTOOLCHAIN = "local" }
+`,
+			expected: []string{
+				`4:1: error: workdir "services/api" is not a directory`,
+			},
+		},
+		{
+			name: "undefined variables",
+			config: `
+[java]
+cli = "${CHEX_UNDEFINED_VARIABLE}/bin/java"
+version = "${file:.java-version}"
+
+[files.jdk]
+path = "${project_root}/${JDK"
+`,
+			expected: []string{
+				`3:1: error: java.cli: undefined variable "CHEX_UNDEFINED_VARIABLE"`,
+				`4:1: error: java.version: cannot read `,
+				`7:1: error: files.jdk.path: unclosed "${" in "${project_root}/${JDK"`,
+			},
+		},
+		{
+			name: "yaml problems",
+			file: ".chex.yaml",
+			config: `
+go:
+  cli: go
+  verison: ">=1.20"
+  optional: "yes"
+node:
+  version: ">=banana"
+`,
+			expected: []string{
+				`4:3: error: unknown key "go.verison" (did you mean "version"?)`,
+				`5:3: error: go.optional: expected boolean, got string`,
+				`6:1: error: [node] has no cli`,
+				`7:3: error: invalid version constraint ">=banana"`,
+			},
+		},
+		{
+			name: "json problems",
+			file: ".chex.json",
+			config: `{
+  "go": {"cli": "go", "version_pattern": "go(\\d+"}
+}
+`,
+			expected: []string{
+				`2:23: error: invalid regular expression "go(\\d+"`,
+			},
+		},
+		{
+			name:   "yaml syntax error",
+			file:   ".chex.yml",
+			config: "go:\n  cli: go\n   version: 1\n",
+			expected: []string{
+				`3:1: error: mapping values are not allowed in this context`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			file := tt.file
+			if file == "" {
+				file = ".chex.toml"
+			}
+			configPath := filepath.Join(tmpDir, file)
+			writeTestFile(t, configPath, tt.config)
+
+			diagnostics, err := Validate(configPath, tmpDir)
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			if len(diagnostics) != len(tt.expected) {
+				t.Fatalf("expected %d diagnostics, got %d: %v", len(tt.expected), len(diagnostics), diagnostics)
+			}
+			for i, diagnostic := range diagnostics {
+				expected := configPath + ":" + tt.expected[i]
+				if !strings.HasPrefix(diagnostic.String(), expected) {
+					t.Errorf("diagnostic %d = %q, expected prefix %q", i, diagnostic, expected)
+				}
+			}
+			if HasErrors(diagnostics) != (len(tt.expected) > 0) {
+				t.Errorf("HasErrors() = %v with %v", HasErrors(diagnostics), diagnostics)
+			}
+		})
+	}
+}
+
+func TestValidateConflicts(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, filepath.Join(tmpDir, ".chex.toml"), `
+[node]
+cli = "node"
+version = ">=18"
+`)
+	writeTestFile(t, filepath.Join(tmpDir, "mise.toml"), `
+[tools]
+node = "16"
+`)
+
+	diagnostics, err := Validate("", tmpDir)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %v", diagnostics)
+	}
+	if diagnostics[0].Severity != SeverityWarning || diagnostics[0].Line != 4 ||
+		!strings.Contains(diagnostics[0].Message, "no version in common") {
+		t.Errorf("expected conflict warning on line 4, got %v", diagnostics[0])
+	}
+	if HasErrors(diagnostics) {
+		t.Error("expected conflicts to be warnings")
+	}
+}
+
+func TestValidateMissingFile(t *testing.T) {
+	_, err := Validate(filepath.Join(t.TempDir(), "missing.toml"), "")
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected not-exist error, got %v", err)
+	}
+}
+
+func TestSuggestKey(t *testing.T) {
+	tests := []struct {
+		key      []string
+		expected string
+	}{
+		{key: []string{"go", "verison"}, expected: "version"},
+		{key: []string{"go", "version_paterns"}, expected: "version_pattern"},
+		{key: []string{"go", "subcommands", "nmae"}, expected: "name"},
+		{key: []string{"services", "db", "adress"}, expected: "address"},
+		{key: []string{"chex", "source"}, expected: "sources"},
+		{key: []string{"go", "zzz"}, expected: ""},
+		{key: []string{"go", "cli", "nested"}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		if got := suggestKey(tt.key); got != tt.expected {
+			t.Errorf("suggestKey(%q) = %q, expected %q", tt.key, got, tt.expected)
+		}
+	}
+}
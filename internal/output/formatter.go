@@ -3,11 +3,14 @@ package output
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/drape-io/chex/internal/checker"
+	"github.com/drape-io/chex/internal/config"
 	"github.com/fatih/color"
 )
 
@@ -15,9 +18,10 @@ import (
 type Format string
 
 const (
-	FormatPretty Format = "pretty"
-	FormatQuiet  Format = "quiet"
-	FormatJSON   Format = "json"
+	FormatPretty  Format = "pretty"
+	FormatQuiet   Format = "quiet"
+	FormatJSON    Format = "json"
+	FormatVerbose Format = "verbose" // pretty, plus how each version command was run
 )
 
 // Print prints the check results in the specified format.
@@ -27,99 +31,358 @@ func Print(results []*checker.Result, format Format) {
 		printJSON(results)
 	case FormatQuiet:
 		printQuiet(results)
+	case FormatVerbose:
+		printPretty(results, true)
 	case FormatPretty:
-		printPretty(results)
+		printPretty(results, false)
 	default:
-		printPretty(results)
+		printPretty(results, false)
 	}
 }
 
-// printPretty prints results in a pretty colored format.
-func printPretty(results []*checker.Result) {
+// categoryOrder lists result categories in the order they are printed.
+var categoryOrder = []checker.Category{
+	checker.CategoryTool,
+	checker.CategoryEnv,
+	checker.CategoryFile,
+	checker.CategoryService,
+}
+
+// categoryHeadings maps each category to the heading printed above its results.
+var categoryHeadings = map[checker.Category]string{
+	checker.CategoryTool:    "Checking CLI Tools...",
+	checker.CategoryEnv:     "Checking Environment Variables...",
+	checker.CategoryFile:    "Checking Files...",
+	checker.CategoryService: "Checking Services...",
+}
+
+// resultCategory returns the category of a result, defaulting to tools.
+func resultCategory(result *checker.Result) checker.Category {
+	if result.Category == "" {
+		return checker.CategoryTool
+	}
+	return result.Category
+}
+
+// groupByCategory splits results by category, preserving their order.
+func groupByCategory(results []*checker.Result) map[checker.Category][]*checker.Result {
+	groups := make(map[checker.Category][]*checker.Result)
+	for _, result := range results {
+		category := resultCategory(result)
+		groups[category] = append(groups[category], result)
+	}
+	return groups
+}
+
+// summary counts results by status.
+type summary struct {
+	passed          int
+	failed          int
+	optionalMissing int
+	skipped         int
+}
+
+// add counts a single result.
+func (s *summary) add(result *checker.Result) {
+	switch result.Status {
+	case checker.StatusPass:
+		s.passed++
+	case checker.StatusFail:
+		s.failed++
+	case checker.StatusOptionalMissing:
+		s.optionalMissing++
+	case checker.StatusSkipped:
+		s.skipped++
+	}
+}
+
+// printPretty prints results in a pretty colored format. Verbose output adds the
+// working directory and environment of each version command.
+func printPretty(results []*checker.Result, verbose bool) {
 	green := color.New(color.FgGreen).SprintFunc()
 	red := color.New(color.FgRed).SprintFunc()
 	yellow := color.New(color.FgYellow).SprintFunc()
 	cyan := color.New(color.FgCyan).SprintFunc()
 
-	fmt.Println("Checking CLI Tools...")
+	counts := summary{}
+	groups := groupByCategory(results)
+
+	for _, category := range categoryOrder {
+		group := groups[category]
+		// Always show the tools heading so an empty run still says what it checked
+		if len(group) == 0 && (category != checker.CategoryTool || len(results) > 0) {
+			continue
+		}
+
+		fmt.Println(categoryHeadings[category])
+		fmt.Println()
+
+		for _, result := range group {
+			counts.add(result)
+			printPrettyResult(result, verbose)
+		}
+	}
+
+	// Print summary
+	fmt.Printf(
+		"Summary: %s passed, %s failed",
+		green(strconv.Itoa(counts.passed)),
+		red(strconv.Itoa(counts.failed)),
+	)
+	if counts.optionalMissing > 0 {
+		fmt.Printf(", %s optional missing", yellow(strconv.Itoa(counts.optionalMissing)))
+	}
+	if counts.skipped > 0 {
+		fmt.Printf(", %s skipped", cyan(strconv.Itoa(counts.skipped)))
+	}
 	fmt.Println()
+}
 
-	passed := 0
-	failed := 0
-	optionalMissing := 0
+// printPrettyResult prints a single result in the pretty format.
+func printPrettyResult(result *checker.Result, verbose bool) {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
 
-	for _, result := range results {
-		tool := result.Tool
+	tool := result.Tool
+
+	// Print tool name with status
+	switch result.Status {
+	case checker.StatusPass:
+		fmt.Printf("%s %s\n", green("✅"), tool.Name)
+	case checker.StatusFail:
+		fmt.Printf("%s %s\n", red("❌"), tool.Name)
+	case checker.StatusOptionalMissing:
+		fmt.Printf("%s %s (optional)\n", yellow("⚠️ "), tool.Name)
+	case checker.StatusSkipped:
+		fmt.Printf("%s %s (skipped)\n", cyan("⏭️ "), tool.Name)
+		fmt.Printf("   Reason: %s\n", result.Reason)
+		fmt.Println()
+		return
+	}
 
-		// Print tool name with status
-		switch result.Status {
-		case checker.StatusPass:
-			fmt.Printf("%s %s\n", green("✅"), tool.Name)
-			passed++
-		case checker.StatusFail:
-			fmt.Printf("%s %s\n", red("❌"), tool.Name)
-			failed++
-		case checker.StatusOptionalMissing:
-			fmt.Printf("%s %s (optional)\n", yellow("⚠️ "), tool.Name)
-			optionalMissing++
+	// Print details
+	if resultCategory(result) == checker.CategoryTool {
+		printToolDetails(result, verbose)
+	} else {
+		printRequirementDetails(result)
+	}
+
+	// Print custom message if available
+	if tool.Message != "" && result.Status != checker.StatusPass {
+		fmt.Printf("   %s %s\n", cyan("Message:"), tool.Message)
+	}
+
+	// Point failures at the definition to fix
+	if location := tool.Location(); location != "" && result.Status != checker.StatusPass {
+		fmt.Printf("   Defined at: %s\n", location)
+	}
+
+	fmt.Println()
+}
+
+// printToolDetails prints the version, path, subcommand and check details of a tool result.
+func printToolDetails(result *checker.Result, verbose bool) {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	// Print details for the CLI that was actually checked
+	checked := result.Tool
+	if result.Selected != nil {
+		checked = result.Selected
+		fmt.Printf("   Selected: %s\n", cyan(checked.CLI))
+	}
+
+	if result.ShimPath != "" {
+		target := result.Path
+		if target == "" {
+			target = red("not installed")
 		}
+		fmt.Printf("   Shim: %s → %s\n", result.ShimPath, target)
+	}
 
-		// Print details
-		if tool.Version != "" {
-			// Version check
-			if result.Output != "" {
-				// Show command and output
-				versionArg := tool.VersionArg
-				if versionArg == "" {
-					versionArg = "version"
-				}
-				fmt.Printf("   $ %s %s\n", tool.CLI, versionArg)
-				firstLine := strings.Split(result.Output, "\n")[0]
-				fmt.Printf("   %s\n", firstLine)
-			}
+	if result.SHA256 != "" {
+		fmt.Printf("   SHA256: %s\n", result.SHA256)
+	}
 
-			if result.Error != nil {
-				fmt.Printf("   %s %s\n", red("Error:"), result.Error)
-			}
+	if result.Binary != nil {
+		fmt.Printf("   Binary: %s\n", describeBinary(result.Binary))
+	}
+
+	if verbose {
+		printCommandEnv(checked)
+	}
+
+	if checked.Version != "" {
+		// Version check
+		if result.Output != "" && checked.VersionSource == config.VersionSourceBuildInfo {
+			// No command was run; the version came from the binary's Go build info
+			fmt.Printf("   Build info: %s\n", result.Output)
+		} else if result.Output != "" {
+			// Show command and output
+			fmt.Printf("   $ %s\n", versionCommandLine(checked))
+			firstLine := strings.Split(result.Output, "\n")[0]
+			fmt.Printf("   %s\n", firstLine)
+		}
 
-			if tool.Version != "" {
-				fmt.Printf("   Required: %s\n", tool.Version)
+		if result.Error != nil {
+			fmt.Printf("   %s %s\n", red("Error:"), result.Error)
+		}
+
+		fmt.Printf("   Required: %s\n", checked.Version)
+
+		// Show where the binary came from when its location is constrained
+		if result.Path != "" && (checked.PathPattern != "" || checked.ManagedBy != "") {
+			fmt.Printf("   Found at: %s\n", cyan(result.Path))
+		}
+
+		if result.InstalledVersion != "" {
+			if result.Status == checker.StatusPass {
+				fmt.Printf("   Installed: %s\n", green(result.InstalledVersion))
+			} else {
+				fmt.Printf("   Installed: %s\n", red(result.InstalledVersion))
 			}
+		}
 
-			if result.InstalledVersion != "" {
-				if result.Status == checker.StatusPass {
-					fmt.Printf("   Installed: %s\n", green(result.InstalledVersion))
-				} else {
-					fmt.Printf("   Installed: %s\n", red(result.InstalledVersion))
-				}
+		// Explain failures caused by policy rather than the version itself
+		if result.Reason != "" && result.InstalledVersion != "" {
+			fmt.Printf("   Reason: %s\n", result.Reason)
+		}
+	} else {
+		// Existence check
+		printRequirementDetails(result)
+	}
+
+	// Print every installation when more than one is on PATH
+	if len(result.Installations) > 1 {
+		fmt.Println("   Installations:")
+		for i, installation := range result.Installations {
+			details := ""
+			if installation.Version != "" {
+				details += " " + installation.Version
+			}
+			if installation.ManagedBy != "" {
+				details += " [" + installation.ManagedBy + "]"
 			}
-		} else {
-			// Existence check
-			if result.Path != "" {
-				fmt.Printf("   Found at: %s\n", cyan(result.Path))
-			} else if result.Error != nil {
-				fmt.Printf("   %s %s\n", red("Error:"), result.Error)
+			if i == 0 {
+				details += " (active)"
 			}
+			fmt.Printf("     %s%s\n", installation.Path, details)
 		}
+	}
 
-		// Print custom message if available
-		if tool.Message != "" && result.Status != checker.StatusPass {
-			fmt.Printf("   %s %s\n", cyan("Message:"), tool.Message)
+	for _, warning := range result.Warnings {
+		fmt.Printf("   %s %s\n", yellow("Warning:"), warning)
+	}
+
+	// Print subcommand and plugin results
+	if len(result.Subcommands) > 0 {
+		fmt.Println("   Subcommands:")
+		for _, sub := range result.Subcommands {
+			printSubcommand(sub)
 		}
+	}
 
-		fmt.Println()
+	// Print custom check results
+	if len(result.Checks) > 0 {
+		fmt.Println("   Checks:")
+		for _, check := range result.Checks {
+			if check.Status == checker.StatusPass {
+				fmt.Printf("     %s %s\n", green("✅"), check.Name)
+			} else {
+				fmt.Printf("     %s %s: %s\n", red("❌"), check.Name, check.Error)
+			}
+		}
 	}
+}
 
-	// Print summary
-	fmt.Printf(
-		"Summary: %s passed, %s failed",
-		green(strconv.Itoa(passed)),
-		red(strconv.Itoa(failed)),
-	)
-	if optionalMissing > 0 {
-		fmt.Printf(", %s optional missing", yellow(strconv.Itoa(optionalMissing)))
+// versionCommandLine describes the command run to get a tool's version.
+func versionCommandLine(tool *config.Tool) string {
+	args := tool.VersionArg
+	if len(tool.VersionArgs) > 0 {
+		args = config.JoinArgs(tool.VersionArgs)
+	}
+	if args == "" {
+		args = "version"
+	}
+	return tool.CLI + " " + args
+}
+
+// printCommandEnv prints the working directory and environment changes a tool's
+// version command runs with.
+func printCommandEnv(tool *config.Tool) {
+	if tool.Workdir != "" {
+		fmt.Printf("   Workdir: %s\n", tool.Workdir)
+	}
+	if len(tool.Env) > 0 {
+		vars := make([]string, 0, len(tool.Env))
+		for _, name := range slices.Sorted(maps.Keys(tool.Env)) {
+			vars = append(vars, name+"="+tool.Env[name])
+		}
+		fmt.Printf("   Env: %s\n", strings.Join(vars, " "))
+	}
+	if len(tool.ClearEnv) > 0 {
+		fmt.Printf("   Cleared env: %s\n", strings.Join(tool.ClearEnv, " "))
+	}
+}
+
+// printRequirementDetails prints where a requirement was found, or why it wasn't.
+func printRequirementDetails(result *checker.Result) {
+	red := color.New(color.FgRed).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	// A binary can be found and still fail its location, checksum or header checks
+	if result.Error != nil {
+		fmt.Printf("   %s %s\n", red("Error:"), result.Error)
+	}
+	if result.Path != "" {
+		fmt.Printf("   Found at: %s\n", cyan(result.Path))
+	}
+}
+
+// describeBinary summarizes an inspected binary, e.g. "elf amd64, dynamic (glibc 2.34)".
+func describeBinary(info *checker.BinaryInfo) string {
+	linking := "dynamic"
+	if info.Static {
+		linking = "static"
+	}
+
+	description := fmt.Sprintf("%s %s, %s", info.Format, strings.Join(info.Archs, "/"), linking)
+	if info.GLIBC != "" {
+		description += fmt.Sprintf(" (glibc %s)", info.GLIBC)
+	}
+	return description
+}
+
+// printSubcommand prints a single subcommand result in the pretty format.
+func printSubcommand(sub *checker.Result) {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	details := ""
+	if sub.InstalledVersion != "" {
+		details = " " + sub.InstalledVersion
+	}
+	if sub.Tool.Version != "" {
+		details += fmt.Sprintf(" (required: %s)", sub.Tool.Version)
+	}
+
+	switch sub.Status {
+	case checker.StatusPass:
+		fmt.Printf("     %s %s%s\n", green("✅"), sub.Tool.Name, details)
+	case checker.StatusOptionalMissing:
+		fmt.Printf("     %s %s%s (optional)\n", yellow("⚠️ "), sub.Tool.Name, details)
+	case checker.StatusFail, checker.StatusSkipped:
+		fmt.Printf("     %s %s%s\n", red("❌"), sub.Tool.Name, details)
+	}
+
+	if sub.Error != nil {
+		fmt.Printf("       %s %s\n", red("Error:"), sub.Error)
 	}
-	fmt.Println()
 }
 
 // printQuiet prints only failures in a compact format.
@@ -128,7 +391,7 @@ func printQuiet(results []*checker.Result) {
 	yellow := color.New(color.FgYellow).SprintFunc()
 
 	for _, result := range results {
-		if result.Status == checker.StatusPass {
+		if result.Status == checker.StatusPass || result.Status == checker.StatusSkipped {
 			continue
 		}
 
@@ -140,7 +403,7 @@ func printQuiet(results []*checker.Result) {
 			fmt.Printf("%s %s\n", red("❌"), tool.Name)
 		case checker.StatusOptionalMissing:
 			fmt.Printf("%s %s (optional)\n", yellow("⚠️ "), tool.Name)
-		case checker.StatusPass:
+		case checker.StatusPass, checker.StatusSkipped:
 			// Already handled by continue above
 		}
 
@@ -149,87 +412,151 @@ func printQuiet(results []*checker.Result) {
 			fmt.Printf("   %s %s\n", red("Error:"), result.Error)
 		}
 
+		// Print failed subcommands
+		for _, sub := range result.Subcommands {
+			if sub.Status == checker.StatusFail {
+				fmt.Printf("   %s %s", red("Subcommand failed:"), sub.Tool.Name)
+				if sub.Error != nil {
+					fmt.Printf(": %s", sub.Error)
+				} else {
+					fmt.Printf(": installed %s, required %s", sub.InstalledVersion, sub.Tool.Version)
+				}
+				fmt.Println()
+			}
+		}
+
+		// Print failed custom checks
+		for _, check := range result.Checks {
+			if check.Status != checker.StatusPass {
+				fmt.Printf("   %s %s: %s\n", red("Check failed:"), check.Name, check.Error)
+			}
+		}
+
 		// Print requirement
 		if tool.Version != "" {
 			fmt.Printf("   Required: %s\n", tool.Version)
 		}
+		if location := tool.Location(); location != "" {
+			fmt.Printf("   Defined at: %s\n", location)
+		}
 
 		fmt.Println()
 	}
 }
 
-// printJSON prints results in JSON format.
-func printJSON(results []*checker.Result) {
-	passed := 0
-	failed := 0
-	optionalMissing := 0
-
-	type JSONTool struct {
-		Name             string `json:"name"`
-		CLI              string `json:"cli"`
-		Required         bool   `json:"required"`
-		Status           string `json:"status"`
-		VersionRequired  string `json:"versionRequired,omitempty"`
-		VersionInstalled string `json:"versionInstalled,omitempty"`
-		Command          string `json:"command,omitempty"`
-		Output           string `json:"output,omitempty"`
-		Path             string `json:"path,omitempty"`
-		Error            string `json:"error,omitempty"`
-		Message          string `json:"message,omitempty"`
-	}
-
-	type JSONOutput struct {
-		Tools   []JSONTool `json:"tools"`
-		Summary struct {
-			Total           int `json:"total"`
-			Passed          int `json:"passed"`
-			Failed          int `json:"failed"`
-			OptionalMissing int `json:"optionalMissing"`
-		} `json:"summary"`
-	}
-
-	output := JSONOutput{}
-	output.Tools = make([]JSONTool, 0, len(results))
+// checkJSON is the JSON representation of a custom check result.
+type checkJSON struct {
+	Name     string `json:"name"`
+	Command  string `json:"command"`
+	Status   string `json:"status"`
+	ExitCode int    `json:"exitCode"`
+	Output   string `json:"output,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
 
-	for _, result := range results {
-		tool := result.Tool
+// subcommandJSON is the JSON representation of a subcommand result.
+type subcommandJSON struct {
+	Name             string `json:"name"`
+	Status           string `json:"status"`
+	VersionRequired  string `json:"versionRequired,omitempty"`
+	VersionInstalled string `json:"versionInstalled,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
 
-		switch result.Status {
-		case checker.StatusPass:
-			passed++
-		case checker.StatusFail:
-			failed++
-		case checker.StatusOptionalMissing:
-			optionalMissing++
-		}
+// installationJSON is the JSON representation of a match for a tool's CLI on PATH.
+type installationJSON struct {
+	Path      string `json:"path"`
+	Version   string `json:"version,omitempty"`
+	ManagedBy string `json:"managedBy,omitempty"`
+}
 
-		jsonTool := JSONTool{
-			Name:             tool.Name,
-			CLI:              tool.CLI,
-			Required:         !tool.Optional,
-			Status:           string(result.Status),
-			VersionRequired:  tool.Version,
-			VersionInstalled: result.InstalledVersion,
-			Path:             result.Path,
-			Message:          tool.Message,
-		}
+// binaryJSON is the JSON representation of an inspected binary's headers.
+type binaryJSON struct {
+	Format string   `json:"format"`
+	Archs  []string `json:"archs"`
+	Static bool     `json:"static"`
+	GLIBC  string   `json:"glibc,omitempty"`
+}
 
-		if result.Error != nil {
-			jsonTool.Error = result.Error.Error()
-		}
+// toolJSON is the JSON representation of a tool result.
+type toolJSON struct {
+	Name             string             `json:"name"`
+	CLI              string             `json:"cli"`
+	Required         bool               `json:"required"`
+	Status           string             `json:"status"`
+	VersionRequired  string             `json:"versionRequired,omitempty"`
+	VersionInstalled string             `json:"versionInstalled,omitempty"`
+	Command          string             `json:"command,omitempty"`
+	VersionSource    string             `json:"versionSource,omitempty"`
+	Output           string             `json:"output,omitempty"`
+	Path             string             `json:"path,omitempty"`
+	Shim             string             `json:"shim,omitempty"`
+	SHA256           string             `json:"sha256,omitempty"`
+	Binary           *binaryJSON        `json:"binary,omitempty"`
+	Selected         string             `json:"selected,omitempty"`
+	Reason           string             `json:"reason,omitempty"`
+	Installations    []installationJSON `json:"installations,omitempty"`
+	Warnings         []string           `json:"warnings,omitempty"`
+	Subcommands      []subcommandJSON   `json:"subcommands,omitempty"`
+	Checks           []checkJSON        `json:"checks,omitempty"`
+	Error            string             `json:"error,omitempty"`
+	Message          string             `json:"message,omitempty"`
+	DefinedAt        string             `json:"definedAt,omitempty"`
+}
 
-		if result.Output != "" && tool.VersionArg != "" {
-			jsonTool.Command = fmt.Sprintf("%s %s", tool.CLI, tool.VersionArg)
-			jsonTool.Output = result.Output
-		}
+// requirementJSON is the JSON representation of a non-tool requirement result.
+type requirementJSON struct {
+	Name     string `json:"name"`
+	Target   string `json:"target"`
+	Required bool   `json:"required"`
+	Status   string `json:"status"`
+	Path     string `json:"path,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
 
-		output.Tools = append(output.Tools, jsonTool)
+// outputJSON is the top-level JSON document.
+type outputJSON struct {
+	Tools    []toolJSON        `json:"tools"`
+	Env      []requirementJSON `json:"env,omitempty"`
+	Files    []requirementJSON `json:"files,omitempty"`
+	Services []requirementJSON `json:"services,omitempty"`
+	Summary  struct {
+		Total           int `json:"total"`
+		Passed          int `json:"passed"`
+		Failed          int `json:"failed"`
+		OptionalMissing int `json:"optionalMissing"`
+		Skipped         int `json:"skipped"`
+	} `json:"summary"`
+}
+
+// printJSON prints results in JSON format.
+func printJSON(results []*checker.Result) {
+	counts := summary{}
+
+	output := outputJSON{}
+	output.Tools = make([]toolJSON, 0, len(results))
+
+	for _, result := range results {
+		counts.add(result)
+
+		switch resultCategory(result) {
+		case checker.CategoryTool:
+			output.Tools = append(output.Tools, toJSONTool(result))
+		case checker.CategoryEnv:
+			output.Env = append(output.Env, toJSONRequirement(result))
+		case checker.CategoryFile:
+			output.Files = append(output.Files, toJSONRequirement(result))
+		case checker.CategoryService:
+			output.Services = append(output.Services, toJSONRequirement(result))
+		}
 	}
 
 	output.Summary.Total = len(results)
-	output.Summary.Passed = passed
-	output.Summary.Failed = failed
-	output.Summary.OptionalMissing = optionalMissing
+	output.Summary.Passed = counts.passed
+	output.Summary.Failed = counts.failed
+	output.Summary.OptionalMissing = counts.optionalMissing
+	output.Summary.Skipped = counts.skipped
 
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetEscapeHTML(false)
@@ -239,6 +566,111 @@ func printJSON(results []*checker.Result) {
 	}
 }
 
+// toJSONTool converts a tool result to its JSON representation.
+func toJSONTool(result *checker.Result) toolJSON {
+	tool := result.Tool
+
+	jsonTool := toolJSON{
+		Name:             tool.Name,
+		CLI:              tool.CLI,
+		Required:         !tool.Optional,
+		Status:           string(result.Status),
+		VersionRequired:  tool.Version,
+		VersionInstalled: result.InstalledVersion,
+		Path:             result.Path,
+		Shim:             result.ShimPath,
+		SHA256:           result.SHA256,
+		Reason:           result.Reason,
+		Message:          tool.Message,
+		DefinedAt:        tool.Location(),
+	}
+
+	if result.Error != nil {
+		jsonTool.Error = result.Error.Error()
+	}
+
+	checked := tool
+	if result.Selected != nil {
+		checked = result.Selected
+		jsonTool.Selected = checked.CLI
+		jsonTool.VersionRequired = checked.Version
+	}
+
+	switch {
+	case result.Output != "" && checked.VersionSource == config.VersionSourceBuildInfo:
+		jsonTool.VersionSource = checked.VersionSource
+		jsonTool.Output = result.Output
+	case result.Output != "":
+		jsonTool.Command = versionCommandLine(checked)
+		jsonTool.Output = result.Output
+	}
+
+	for _, installation := range result.Installations {
+		jsonTool.Installations = append(jsonTool.Installations, installationJSON{
+			Path:      installation.Path,
+			Version:   installation.Version,
+			ManagedBy: installation.ManagedBy,
+		})
+	}
+	jsonTool.Warnings = result.Warnings
+
+	if result.Binary != nil {
+		jsonTool.Binary = &binaryJSON{
+			Format: result.Binary.Format,
+			Archs:  result.Binary.Archs,
+			Static: result.Binary.Static,
+			GLIBC:  result.Binary.GLIBC,
+		}
+	}
+
+	for _, sub := range result.Subcommands {
+		jsonSub := subcommandJSON{
+			Name:             sub.Tool.Name,
+			Status:           string(sub.Status),
+			VersionRequired:  sub.Tool.Version,
+			VersionInstalled: sub.InstalledVersion,
+		}
+		if sub.Error != nil {
+			jsonSub.Error = sub.Error.Error()
+		}
+		jsonTool.Subcommands = append(jsonTool.Subcommands, jsonSub)
+	}
+
+	for _, check := range result.Checks {
+		jsonCheck := checkJSON{
+			Name:     check.Name,
+			Command:  check.Command,
+			Status:   string(check.Status),
+			ExitCode: check.ExitCode,
+			Output:   check.Output,
+		}
+		if check.Error != nil {
+			jsonCheck.Error = check.Error.Error()
+		}
+		jsonTool.Checks = append(jsonTool.Checks, jsonCheck)
+	}
+
+	return jsonTool
+}
+
+// toJSONRequirement converts a non-tool requirement result to its JSON representation.
+func toJSONRequirement(result *checker.Result) requirementJSON {
+	requirement := requirementJSON{
+		Name:     result.Tool.Name,
+		Target:   result.Tool.CLI,
+		Required: !result.Tool.Optional,
+		Status:   string(result.Status),
+		Path:     result.Path,
+		Message:  result.Tool.Message,
+	}
+
+	if result.Error != nil {
+		requirement.Error = result.Error.Error()
+	}
+
+	return requirement
+}
+
 // ShouldExitWithError determines if chex should exit with error code 1.
 func ShouldExitWithError(results []*checker.Result) bool {
 	for _, result := range results {
@@ -235,6 +235,402 @@ func TestPrintJSON(t *testing.T) {
 			t.Error("expected camelCase 'optionalMissing' in JSON")
 		}
 	})
+
+	t.Run("includes the command for version_args", func(t *testing.T) {
+		results := []*checker.Result{
+			{
+				Tool: &config.Tool{
+					Name:        "kubectl",
+					CLI:         "kubectl",
+					Version:     ">=1.28.0",
+					VersionArgs: []string{"version", "--client", "--output=yaml"},
+				},
+				Status:           checker.StatusPass,
+				InstalledVersion: "1.29.0",
+				Output:           "clientVersion:\n  gitVersion: v1.29.0",
+			},
+		}
+
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		printJSON(results)
+
+		_ = w.Close()
+		os.Stdout = old
+
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+
+		var jsonOutput struct {
+			Tools []struct {
+				Command string `json:"command"`
+				Output  string `json:"output"`
+			} `json:"tools"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &jsonOutput); err != nil {
+			t.Fatalf("failed to parse JSON: %v", err)
+		}
+
+		tool := jsonOutput.Tools[0]
+		if tool.Command != "kubectl version --client --output=yaml" {
+			t.Errorf("expected the version_args command, got %q", tool.Command)
+		}
+		if tool.Output != results[0].Output {
+			t.Errorf("expected the command output, got %q", tool.Output)
+		}
+	})
+}
+
+func TestPrintSelectedAlternative(t *testing.T) {
+	results := []*checker.Result{
+		{
+			Tool: &config.Tool{
+				Name:         "docker",
+				CLI:          "docker",
+				Version:      ">=20.0.0",
+				Alternatives: []config.Alternative{{CLI: "podman", Version: ">=4.0.0"}},
+			},
+			Selected: &config.Tool{
+				Name:       "docker",
+				CLI:        "podman",
+				Version:    ">=4.0.0",
+				VersionArg: "--version",
+			},
+			Status:           checker.StatusPass,
+			InstalledVersion: "4.9.3",
+			Output:           "podman version 4.9.3",
+		},
+	}
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	printJSON(results)
+
+	_ = w.Close()
+	os.Stdout = old
+
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+
+	var jsonOutput struct {
+		Tools []struct {
+			Selected        string `json:"selected"`
+			VersionRequired string `json:"versionRequired"`
+			Command         string `json:"command"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &jsonOutput); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+
+	tool := jsonOutput.Tools[0]
+	if tool.Selected != "podman" {
+		t.Errorf("expected selected 'podman', got %q", tool.Selected)
+	}
+	if tool.VersionRequired != ">=4.0.0" {
+		t.Errorf("expected selected constraint, got %q", tool.VersionRequired)
+	}
+	if tool.Command != "podman --version" {
+		t.Errorf("expected selected command, got %q", tool.Command)
+	}
+}
+
+func TestPrintSkipped(t *testing.T) {
+	results := []*checker.Result{
+		{
+			Tool: &config.Tool{
+				Name: "hadolint",
+				CLI:  "hadolint",
+			},
+			Status: checker.StatusSkipped,
+			Reason: "Dockerfile does not exist",
+		},
+	}
+
+	t.Run("pretty format shows reason", func(t *testing.T) {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		printPretty(results, false)
+
+		_ = w.Close()
+		os.Stdout = old
+
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		output := buf.String()
+
+		if !strings.Contains(output, "(skipped)") {
+			t.Error("expected output to mark the tool as skipped")
+		}
+		if !strings.Contains(output, "Dockerfile does not exist") {
+			t.Error("expected output to contain the skip reason")
+		}
+	})
+
+	t.Run("json format counts skipped", func(t *testing.T) {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		printJSON(results)
+
+		_ = w.Close()
+		os.Stdout = old
+
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+
+		var jsonOutput struct {
+			Tools []struct {
+				Status string `json:"status"`
+				Reason string `json:"reason"`
+			} `json:"tools"`
+			Summary struct {
+				Skipped int `json:"skipped"`
+			} `json:"summary"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &jsonOutput); err != nil {
+			t.Fatalf("failed to parse JSON: %v", err)
+		}
+
+		if jsonOutput.Summary.Skipped != 1 {
+			t.Errorf("expected 1 skipped, got %d", jsonOutput.Summary.Skipped)
+		}
+		if jsonOutput.Tools[0].Reason != "Dockerfile does not exist" {
+			t.Errorf("expected reason in JSON, got %q", jsonOutput.Tools[0].Reason)
+		}
+	})
+
+	t.Run("skipped does not fail", func(t *testing.T) {
+		if ShouldExitWithError(results) {
+			t.Error("expected skipped tools not to cause an error exit")
+		}
+	})
+}
+
+func TestPrintBinaryInfo(t *testing.T) {
+	results := []*checker.Result{
+		{
+			Tool: &config.Tool{
+				Name: "terraform",
+				CLI:  "terraform",
+				Arch: "native",
+			},
+			Status: checker.StatusFail,
+			Path:   "/usr/local/bin/terraform",
+			Binary: &checker.BinaryInfo{Format: "elf", Archs: []string{"amd64"}, GLIBC: "2.34"},
+			Error:  errors.New("binary architecture is amd64, expected arm64"),
+		},
+	}
+
+	t.Run("pretty format shows headers and error", func(t *testing.T) {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		printPretty(results, false)
+
+		_ = w.Close()
+		os.Stdout = old
+
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		output := buf.String()
+
+		if !strings.Contains(output, "elf amd64, dynamic (glibc 2.34)") {
+			t.Errorf("expected binary summary in output, got %q", output)
+		}
+		if !strings.Contains(output, "expected arm64") {
+			t.Error("expected output to contain the error")
+		}
+	})
+
+	t.Run("json format includes binary", func(t *testing.T) {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		printJSON(results)
+
+		_ = w.Close()
+		os.Stdout = old
+
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+
+		var jsonOutput struct {
+			Tools []struct {
+				Binary *binaryJSON `json:"binary"`
+			} `json:"tools"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &jsonOutput); err != nil {
+			t.Fatalf("failed to parse JSON: %v", err)
+		}
+
+		binary := jsonOutput.Tools[0].Binary
+		if binary == nil || binary.Format != "elf" || binary.Static || binary.GLIBC != "2.34" {
+			t.Errorf("unexpected binary in JSON: %+v", binary)
+		}
+	})
+}
+
+func TestPrintPrereleasePolicy(t *testing.T) {
+	results := []*checker.Result{
+		{
+			Tool: &config.Tool{
+				Name:    "node",
+				CLI:     "node",
+				Version: ">=20",
+			},
+			Status:           checker.StatusFail,
+			InstalledVersion: "23.0.0-nightly+homebrew",
+			Output:           "v23.0.0-nightly+homebrew",
+			Reason:           "prerelease excluded by policy",
+		},
+	}
+
+	t.Run("pretty format shows policy reason", func(t *testing.T) {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		printPretty(results, false)
+
+		_ = w.Close()
+		os.Stdout = old
+
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		output := buf.String()
+
+		if !strings.Contains(output, "Reason: prerelease excluded by policy") {
+			t.Errorf("expected policy reason in output, got %q", output)
+		}
+		if !strings.Contains(output, "23.0.0-nightly+homebrew") {
+			t.Error("expected build metadata in output")
+		}
+	})
+
+	t.Run("json format preserves build metadata", func(t *testing.T) {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		printJSON(results)
+
+		_ = w.Close()
+		os.Stdout = old
+
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+
+		var jsonOutput struct {
+			Tools []struct {
+				VersionInstalled string `json:"versionInstalled"`
+				Reason           string `json:"reason"`
+			} `json:"tools"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &jsonOutput); err != nil {
+			t.Fatalf("failed to parse JSON: %v", err)
+		}
+
+		tool := jsonOutput.Tools[0]
+		if tool.VersionInstalled != "23.0.0-nightly+homebrew" {
+			t.Errorf("expected build metadata preserved, got %q", tool.VersionInstalled)
+		}
+		if tool.Reason != "prerelease excluded by policy" {
+			t.Errorf("expected policy reason in JSON, got %q", tool.Reason)
+		}
+	})
+}
+
+func TestPrintEnvCategory(t *testing.T) {
+	results := []*checker.Result{
+		{
+			Category: checker.CategoryTool,
+			Tool:     &config.Tool{Name: "go", CLI: "go"},
+			Status:   checker.StatusPass,
+			Path:     "/usr/local/go/bin/go",
+		},
+		{
+			Category: checker.CategoryEnv,
+			Tool:     &config.Tool{Name: "GOPRIVATE", CLI: "GOPRIVATE"},
+			Status:   checker.StatusFail,
+			Error:    errors.New("GOPRIVATE is not set"),
+		},
+	}
+
+	t.Run("pretty format groups by category", func(t *testing.T) {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		printPretty(results, false)
+
+		_ = w.Close()
+		os.Stdout = old
+
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		output := buf.String()
+
+		toolsIdx := strings.Index(output, "Checking CLI Tools...")
+		envIdx := strings.Index(output, "Checking Environment Variables...")
+		if toolsIdx == -1 || envIdx == -1 || envIdx < toolsIdx {
+			t.Errorf("expected tools heading followed by env heading, got:\n%s", output)
+		}
+		if !strings.Contains(output, "GOPRIVATE is not set") {
+			t.Error("expected env error in output")
+		}
+	})
+
+	t.Run("json format lists env separately", func(t *testing.T) {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		printJSON(results)
+
+		_ = w.Close()
+		os.Stdout = old
+
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+
+		var jsonOutput struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+			Env []struct {
+				Name   string `json:"name"`
+				Target string `json:"target"`
+				Status string `json:"status"`
+			} `json:"env"`
+			Summary struct {
+				Total  int `json:"total"`
+				Failed int `json:"failed"`
+			} `json:"summary"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &jsonOutput); err != nil {
+			t.Fatalf("failed to parse JSON: %v", err)
+		}
+
+		if len(jsonOutput.Tools) != 1 || len(jsonOutput.Env) != 1 {
+			t.Fatalf("expected 1 tool and 1 env result, got %d and %d", len(jsonOutput.Tools), len(jsonOutput.Env))
+		}
+		if jsonOutput.Env[0].Target != "GOPRIVATE" || jsonOutput.Env[0].Status != "fail" {
+			t.Errorf("unexpected env result: %+v", jsonOutput.Env[0])
+		}
+		if jsonOutput.Summary.Total != 2 || jsonOutput.Summary.Failed != 1 {
+			t.Errorf("unexpected summary: %+v", jsonOutput.Summary)
+		}
+	})
 }
 
 func TestShouldExitWithError(t *testing.T) {
@@ -292,3 +688,102 @@ func TestShouldExitWithError(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintDefinedAt(t *testing.T) {
+	tool := &config.Tool{
+		Name:     "go",
+		CLI:      "go",
+		Version:  ">=1.22",
+		File:     ".chex.toml",
+		Position: config.Position{Line: 7, Col: 1},
+	}
+
+	capture := func(print func()) string {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		print()
+
+		_ = w.Close()
+		os.Stdout = old
+
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		return buf.String()
+	}
+
+	t.Run("failures point at the definition", func(t *testing.T) {
+		results := []*checker.Result{{Tool: tool, Status: checker.StatusFail, InstalledVersion: "1.21.0"}}
+
+		output := capture(func() { printPretty(results, false) })
+		if !strings.Contains(output, "Defined at: .chex.toml:7:1") {
+			t.Errorf("expected definition location in pretty output, got %q", output)
+		}
+		if output := capture(func() { printQuiet(results) }); !strings.Contains(output, "Defined at: .chex.toml:7:1") {
+			t.Errorf("expected definition location in quiet output, got %q", output)
+		}
+	})
+
+	t.Run("passing tools don't", func(t *testing.T) {
+		results := []*checker.Result{{Tool: tool, Status: checker.StatusPass, InstalledVersion: "1.22.0"}}
+
+		if output := capture(func() { printPretty(results, false) }); strings.Contains(output, "Defined at") {
+			t.Errorf("expected no definition location for passing tool, got %q", output)
+		}
+	})
+
+	t.Run("json includes the definition", func(t *testing.T) {
+		results := []*checker.Result{{Tool: tool, Status: checker.StatusPass}}
+
+		if output := capture(func() { printJSON(results) }); !strings.Contains(output, `"definedAt": ".chex.toml:7:1"`) {
+			t.Errorf("expected definedAt in JSON output, got %q", output)
+		}
+	})
+}
+
+func TestPrintVerbose(t *testing.T) {
+	tool := &config.Tool{
+		Name:        "helm",
+		CLI:         "helm",
+		Version:     ">=3.0.0",
+		VersionArgs: []string{"version", "--template", "{{.Version}}"},
+		Env:         map[string]string{"HELM_DEBUG": "false", "HELM_CACHE_HOME": "/tmp/helm"},
+		ClearEnv:    []string{"KUBECONFIG"},
+		Workdir:     "/project/charts",
+	}
+	results := []*checker.Result{{
+		Tool: tool, Status: checker.StatusPass, InstalledVersion: "3.14.0", Output: "v3.14.0",
+	}}
+
+	capture := func(format Format) string {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		Print(results, format)
+
+		_ = w.Close()
+		os.Stdout = old
+
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		return buf.String()
+	}
+
+	output := capture(FormatVerbose)
+	for _, expected := range []string{
+		"Workdir: /project/charts",
+		"Env: HELM_CACHE_HOME=/tmp/helm HELM_DEBUG=false",
+		"Cleared env: KUBECONFIG",
+		"$ helm version --template '{{.Version}}'",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %q in verbose output, got %q", expected, output)
+		}
+	}
+
+	if output := capture(FormatPretty); strings.Contains(output, "Workdir") || strings.Contains(output, "Env:") {
+		t.Errorf("expected no command environment in pretty output, got %q", output)
+	}
+}